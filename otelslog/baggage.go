@@ -0,0 +1,93 @@
+/*
+Copyright Agoda Services Co.,Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package otelslog
+
+import (
+	"context"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	"path"
+	"sort"
+	"unicode/utf8"
+)
+
+// appendBaggage appends members of the context baggage allowed by the options to dst
+func (o otelHandler) appendBaggage(dst []attribute.KeyValue, ctx context.Context) []attribute.KeyValue {
+	members := baggage.FromContext(ctx).Members()
+	if len(members) == 0 {
+		return dst
+	}
+	// baggage keeps members in a map, sort them so the limit always keeps the same members
+	sort.Slice(members, func(i, j int) bool {
+		return members[i].Key() < members[j].Key()
+	})
+
+	added := 0
+	for _, member := range members {
+		if o.opts.BaggageMaxMembers > 0 && added >= o.opts.BaggageMaxMembers {
+			break
+		}
+		if !o.baggageAllowed(member.Key()) {
+			continue
+		}
+		added++
+
+		key := o.opts.BaggagePrefix + member.Key()
+		dst = append(dst, attribute.String(key, o.truncateBaggage(member.Value())))
+		if !o.opts.BaggageProperties {
+			continue
+		}
+		for _, property := range member.Properties() {
+			if value, ok := property.Value(); ok {
+				dst = append(dst, attribute.String(key+"."+property.Key(), o.truncateBaggage(value)))
+			} else {
+				dst = append(dst, attribute.Bool(key+"."+property.Key(), true))
+			}
+		}
+	}
+	return dst
+}
+
+// baggageAllowed reports whether member key passes BaggageAllow and BaggageDeny patterns
+func (o otelHandler) baggageAllowed(key string) bool {
+	for _, pattern := range o.opts.BaggageDeny {
+		if matched, _ := path.Match(pattern, key); matched {
+			return false
+		}
+	}
+	if len(o.opts.BaggageAllow) == 0 {
+		return true
+	}
+	for _, pattern := range o.opts.BaggageAllow {
+		if matched, _ := path.Match(pattern, key); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// truncateBaggage shortens value to BaggageMaxValueLength bytes without splitting UTF-8 characters
+func (o otelHandler) truncateBaggage(value string) string {
+	limit := o.opts.BaggageMaxValueLength
+	if limit <= 0 || len(value) <= limit {
+		return value
+	}
+	for limit > 0 && !utf8.RuneStart(value[limit]) {
+		limit--
+	}
+	return value[:limit]
+}
@@ -17,32 +17,173 @@ limitations under the License.
 package otelslog
 
 import (
+	"encoding/base64"
+	"fmt"
 	"go.opentelemetry.io/otel/attribute"
 	"log/slog"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
 )
 
+// maxAttributeDepth bounds the nesting of groups produced by LogValuers,
+// so a value that resolves to a group containing itself cannot recurse forever
+const maxAttributeDepth = 16
+
+// otelAttribute convert slog Attr into OpenTelemetry Attributes
 func otelAttribute(attr slog.Attr) []attribute.KeyValue {
-	switch attr.Value.Kind() {
+	return appendOtelAttribute(nil, "", attr, 0)
+}
+
+// appendOtelAttribute resolves attr and appends its OpenTelemetry representation with the key prefixed by prefix
+// to dst. Attributes with empty keys and empty groups are ignored, groups with empty keys are inlined.
+func appendOtelAttribute(dst []attribute.KeyValue, prefix string, attr slog.Attr, depth int) []attribute.KeyValue {
+	// Resolve calls LogValue repeatedly and turns cycles and panics into error values
+	value := attr.Value.Resolve()
+
+	if value.Kind() == slog.KindGroup {
+		if attr.Key != "" {
+			prefix += attr.Key + "."
+		}
+		if depth >= maxAttributeDepth {
+			return append(dst, attribute.String(strings.TrimSuffix(prefix, "."), "!MAXDEPTH"))
+		}
+		for _, member := range value.Group() {
+			dst = appendOtelAttribute(dst, prefix, member, depth+1)
+		}
+		return dst
+	}
+
+	if attr.Key == "" {
+		return dst
+	}
+	key := prefix + attr.Key
+
+	switch value.Kind() {
 	case slog.KindBool:
-		return []attribute.KeyValue{attribute.Bool(attr.Key, attr.Value.Bool())}
-	//case slog.KindDuration: ???
+		return append(dst, attribute.Bool(key, value.Bool()))
+	case slog.KindDuration:
+		return append(dst, attribute.Float64(key, value.Duration().Seconds()))
 	case slog.KindFloat64:
-		return []attribute.KeyValue{attribute.Float64(attr.Key, attr.Value.Float64())}
+		return append(dst, attribute.Float64(key, value.Float64()))
 	case slog.KindInt64:
-		return []attribute.KeyValue{attribute.Int64(attr.Key, attr.Value.Int64())}
+		return append(dst, attribute.Int64(key, value.Int64()))
 	case slog.KindString:
-		return []attribute.KeyValue{attribute.String(attr.Key, attr.Value.String())}
-	//case slog.KindTime: ???
+		return append(dst, attribute.String(key, value.String()))
+	case slog.KindTime:
+		return append(dst, attribute.Int64(key, value.Time().Unix()))
 	case slog.KindUint64:
-		return []attribute.KeyValue{attribute.Int64(attr.Key, int64(attr.Value.Uint64()))}
-	case slog.KindGroup:
-		group := attr.Value.Group()
-		var result []attribute.KeyValue
-		for _, v := range group {
-			v.Key = attr.Key + "." + v.Key
-			result = append(result, otelAttribute(v)...)
+		return append(dst, otelUint64(key, value.Uint64()))
+	case slog.KindAny:
+		return appendOtelAny(dst, key, value.Any())
+	}
+	return append(dst, attribute.String(key, value.String()))
+}
+
+// appendOtelAny converts values of slog.KindAny, keeping the type information where OpenTelemetry has a matching
+// attribute type
+func appendOtelAny(dst []attribute.KeyValue, key string, value any) []attribute.KeyValue {
+	if isNil(value) {
+		return append(dst, attribute.String(key, "<nil>"))
+	}
+
+	switch v := value.(type) {
+	case error:
+		return appendException(dst, key, v)
+	case []byte:
+		return append(dst, attribute.String(key, base64.StdEncoding.EncodeToString(v)))
+	case fmt.Stringer:
+		return append(dst, attribute.Stringer(key, v))
+	case []bool:
+		return append(dst, attribute.BoolSlice(key, v))
+	case []int:
+		return append(dst, attribute.IntSlice(key, v))
+	case []int64:
+		return append(dst, attribute.Int64Slice(key, v))
+	case []float64:
+		return append(dst, attribute.Float64Slice(key, v))
+	case []string:
+		return append(dst, attribute.StringSlice(key, v))
+	}
+
+	if kv, ok := otelSlice(key, reflect.ValueOf(value)); ok {
+		return append(dst, kv)
+	}
+	return append(dst, attribute.String(key, fmt.Sprintf("%+v", value)))
+}
+
+// otelSlice converts slices and arrays of primitive kinds not covered by the fast path in appendOtelAny
+func otelSlice(key string, v reflect.Value) (attribute.KeyValue, bool) {
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return attribute.KeyValue{}, false
+	}
+
+	switch v.Type().Elem().Kind() {
+	case reflect.Bool:
+		result := make([]bool, v.Len())
+		for i := range result {
+			result[i] = v.Index(i).Bool()
+		}
+		return attribute.BoolSlice(key, result), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		result := make([]int64, v.Len())
+		for i := range result {
+			result[i] = v.Index(i).Int()
 		}
-		return result
+		return attribute.Int64Slice(key, result), true
+	case reflect.Uint, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		result := make([]int64, v.Len())
+		for i := range result {
+			u := v.Index(i).Uint()
+			if u > math.MaxInt64 {
+				return otelUint64Strings(key, v), true
+			}
+			result[i] = int64(u)
+		}
+		return attribute.Int64Slice(key, result), true
+	case reflect.Float32, reflect.Float64:
+		result := make([]float64, v.Len())
+		for i := range result {
+			result[i] = v.Index(i).Float()
+		}
+		return attribute.Float64Slice(key, result), true
+	case reflect.String:
+		result := make([]string, v.Len())
+		for i := range result {
+			result[i] = v.Index(i).String()
+		}
+		return attribute.StringSlice(key, result), true
+	}
+	return attribute.KeyValue{}, false
+}
+
+// otelUint64 converts value into int64 attribute, values above math.MaxInt64 don't fit and are exported as decimal string
+func otelUint64(key string, value uint64) attribute.KeyValue {
+	if value > math.MaxInt64 {
+		return attribute.String(key, strconv.FormatUint(value, 10))
+	}
+	return attribute.Int64(key, int64(value))
+}
+
+// otelUint64Strings converts slice of unsigned integers with values above math.MaxInt64 into decimal strings
+func otelUint64Strings(key string, v reflect.Value) attribute.KeyValue {
+	result := make([]string, v.Len())
+	for i := range result {
+		result[i] = strconv.FormatUint(v.Index(i).Uint(), 10)
+	}
+	return attribute.StringSlice(key, result)
+}
+
+// isNil reports whether value is nil or a typed nil pointer, which would panic on method calls
+func isNil(value any) bool {
+	if value == nil {
+		return true
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Func, reflect.Chan:
+		return v.IsNil()
 	}
-	return []attribute.KeyValue{attribute.String(attr.Key, attr.Value.String())}
+	return false
 }
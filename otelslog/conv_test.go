@@ -0,0 +1,165 @@
+package otelslog
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+)
+
+var (
+	testAttrKey = "test-123"
+	testNow     = time.Now()
+)
+
+type testUser struct {
+	ID   int
+	Name string
+}
+
+func (u testUser) LogValue() slog.Value {
+	return slog.GroupValue(slog.Int("id", u.ID), slog.String("name", u.Name))
+}
+
+type testToken string
+
+func (t testToken) LogValue() slog.Value {
+	return slog.StringValue("REDACTED")
+}
+
+// testCycle resolves to a group containing itself
+type testCycle struct{}
+
+func (c testCycle) LogValue() slog.Value {
+	return slog.GroupValue(slog.Any("next", c))
+}
+
+type testLevels []uint16
+
+// testStackError mimics errors of github.com/pkg/errors
+type testStackError struct {
+	error
+}
+
+func (e testStackError) Unwrap() error {
+	return e.error
+}
+
+func (e testStackError) StackTrace() testStackTrace {
+	return testStackTrace{}
+}
+
+type testStackTrace struct{}
+
+func (s testStackTrace) Format(f fmt.State, verb rune) {
+	if f.Flag('+') {
+		_, _ = f.Write([]byte("main.main\n\tmain.go:1"))
+	}
+}
+
+func TestOTeLAttributeMapping(t *testing.T) {
+	tests := []struct {
+		Input    slog.Attr
+		Expected []attribute.KeyValue
+	}{
+		{Input: slog.Bool(testAttrKey, true), Expected: []attribute.KeyValue{attribute.Bool(testAttrKey, true)}},
+		{Input: slog.Float64(testAttrKey, 123.123), Expected: []attribute.KeyValue{attribute.Float64(testAttrKey, 123.123)}},
+		{Input: slog.Int(testAttrKey, 123), Expected: []attribute.KeyValue{attribute.Int64(testAttrKey, 123)}},
+		{Input: slog.Uint64(testAttrKey, 123), Expected: []attribute.KeyValue{attribute.Int64(testAttrKey, 123)}},
+		{Input: slog.Uint64(testAttrKey, math.MaxUint64), Expected: []attribute.KeyValue{attribute.String(testAttrKey, "18446744073709551615")}},
+		{Input: slog.String(testAttrKey, "hello"), Expected: []attribute.KeyValue{attribute.String(testAttrKey, "hello")}},
+		{Input: slog.Duration(testAttrKey, time.Minute), Expected: []attribute.KeyValue{attribute.Float64(testAttrKey, time.Minute.Seconds())}},
+		{Input: slog.Time(testAttrKey, testNow), Expected: []attribute.KeyValue{attribute.Int64(testAttrKey, testNow.Unix())}},
+		{Input: slog.Group(testAttrKey, slog.String("a", "b"), slog.Group("c", slog.Int("d", 1))), Expected: []attribute.KeyValue{attribute.String(testAttrKey+".a", "b"), attribute.Int64(testAttrKey+".c.d", 1)}},
+		{Input: slog.Group("", slog.String("a", "b")), Expected: []attribute.KeyValue{attribute.String("a", "b")}},
+		{Input: slog.Group(testAttrKey), Expected: []attribute.KeyValue{}},
+		{Input: slog.String("", "hello"), Expected: []attribute.KeyValue{}},
+		{Input: slog.Any(testAttrKey, testUser{ID: 1, Name: "john"}), Expected: []attribute.KeyValue{attribute.Int64(testAttrKey+".id", 1), attribute.String(testAttrKey+".name", "john")}},
+		{Input: slog.Any(testAttrKey, testToken("secret")), Expected: []attribute.KeyValue{attribute.String(testAttrKey, "REDACTED")}},
+		{Input: slog.Any(testAttrKey, []byte{1, 0, 0, 1}), Expected: []attribute.KeyValue{attribute.String(testAttrKey, "AQAAAQ==")}},
+		{Input: slog.Any(testAttrKey, []bool{true, false}), Expected: []attribute.KeyValue{attribute.BoolSlice(testAttrKey, []bool{true, false})}},
+		{Input: slog.Any(testAttrKey, []int{1, 2}), Expected: []attribute.KeyValue{attribute.Int64Slice(testAttrKey, []int64{1, 2})}},
+		{Input: slog.Any(testAttrKey, []int32{1, 2}), Expected: []attribute.KeyValue{attribute.Int64Slice(testAttrKey, []int64{1, 2})}},
+		{Input: slog.Any(testAttrKey, testLevels{1, 2}), Expected: []attribute.KeyValue{attribute.Int64Slice(testAttrKey, []int64{1, 2})}},
+		{Input: slog.Any(testAttrKey, []uint64{1, math.MaxUint64}), Expected: []attribute.KeyValue{attribute.StringSlice(testAttrKey, []string{"1", "18446744073709551615"})}},
+		{Input: slog.Any(testAttrKey, [2]float32{1.5, 2.5}), Expected: []attribute.KeyValue{attribute.Float64Slice(testAttrKey, []float64{1.5, 2.5})}},
+		{Input: slog.Any(testAttrKey, []string{"a", "b"}), Expected: []attribute.KeyValue{attribute.StringSlice(testAttrKey, []string{"a", "b"})}},
+		{Input: slog.Any(testAttrKey, time.UTC), Expected: []attribute.KeyValue{attribute.String(testAttrKey, "UTC")}},
+		{Input: slog.Any(testAttrKey, map[string]int{"a": 1}), Expected: []attribute.KeyValue{attribute.String(testAttrKey, "map[a:1]")}},
+		{Input: slog.Any(testAttrKey, nil), Expected: []attribute.KeyValue{attribute.String(testAttrKey, "<nil>")}},
+		{Input: slog.Any(testAttrKey, (*int)(nil)), Expected: []attribute.KeyValue{attribute.String(testAttrKey, "<nil>")}},
+		{Input: slog.Any("err", errors.New("world")), Expected: []attribute.KeyValue{semconv.ExceptionMessage("world"), semconv.ExceptionType("*errors.errorString")}},
+		{Input: slog.Any("error", testStackError{errors.New("world")}), Expected: []attribute.KeyValue{
+			semconv.ExceptionMessage("world"),
+			semconv.ExceptionType("otelslog.testStackError"),
+			semconv.ExceptionStacktrace("main.main\n\tmain.go:1"),
+			ExceptionCauseTypeKey.StringSlice([]string{"*errors.errorString"}),
+			ExceptionCauseMessageKey.StringSlice([]string{"world"}),
+		}},
+		{Input: slog.Any("err", fmt.Errorf("read config: %w", errors.Join(testStackError{os.ErrNotExist}, os.ErrPermission))), Expected: []attribute.KeyValue{
+			semconv.ExceptionMessage("read config: file does not exist\npermission denied"),
+			semconv.ExceptionType("*fmt.wrapError"),
+			semconv.ExceptionStacktrace("main.main\n\tmain.go:1"),
+			ExceptionCauseTypeKey.StringSlice([]string{"*errors.joinError", "otelslog.testStackError", "*errors.errorString", "*errors.errorString"}),
+			ExceptionCauseMessageKey.StringSlice([]string{"file does not exist\npermission denied", "file does not exist", "permission denied", "file does not exist"}),
+		}},
+		{Input: slog.Any(testAttrKey, errors.New("world")), Expected: []attribute.KeyValue{
+			attribute.String(testAttrKey+".message", "world"),
+			attribute.String(testAttrKey+".type", "*errors.errorString"),
+		}},
+		{Input: slog.Group("", slog.Group("db", slog.Any("err", testStackError{os.ErrNotExist})), slog.Any("cause", os.ErrPermission)), Expected: []attribute.KeyValue{
+			attribute.String("db.err.message", "file does not exist"),
+			attribute.String("db.err.type", "otelslog.testStackError"),
+			attribute.String("db.err.stacktrace", "main.main\n\tmain.go:1"),
+			attribute.StringSlice("db.err.cause.type", []string{"*errors.errorString"}),
+			attribute.StringSlice("db.err.cause.message", []string{"file does not exist"}),
+			attribute.String("cause.message", "permission denied"),
+			attribute.String("cause.type", "*errors.errorString"),
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%+v", test.Input), func(t *testing.T) {
+			output := otelAttribute(test.Input)
+			assert.ElementsMatch(t, test.Expected, output)
+		})
+	}
+}
+
+// testMultiError keeps its errors in a slice returned by Unwrap
+type testMultiError struct {
+	errs []error
+}
+
+func (e testMultiError) Error() string {
+	return "multi"
+}
+
+func (e testMultiError) Unwrap() []error {
+	return e.errs
+}
+
+func TestOTeLAttributeMapping_ErrorNotModified(t *testing.T) {
+	cause := errors.New("cause")
+	err := testMultiError{errs: []error{nil, cause}}
+
+	output := otelAttribute(slog.Any("err", err))
+
+	assert.Equal(t, []error{nil, cause}, err.errs)
+	assert.Contains(t, output, ExceptionCauseMessageKey.StringSlice([]string{"cause"}))
+}
+
+func TestOTeLAttributeMapping_LogValuerCycle(t *testing.T) {
+	output := otelAttribute(slog.Any(testAttrKey, testCycle{}))
+
+	assert.Len(t, output, 1)
+	assert.Equal(t, attribute.String(testAttrKey+strings.Repeat(".next", maxAttributeDepth), "!MAXDEPTH"), output[0])
+}
@@ -0,0 +1,148 @@
+/*
+Copyright Agoda Services Co.,Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package otelslog
+
+import (
+	"fmt"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"reflect"
+)
+
+// maxExceptionCauses bounds the number of causes exported for a single error
+const maxExceptionCauses = 10
+
+const (
+	// ExceptionCauseTypeKey lists Go types of the errors wrapped by the logged error,
+	// in breadth-first order of Unwrap and errors.Join chains
+	ExceptionCauseTypeKey = attribute.Key("exception.cause.type")
+	// ExceptionCauseMessageKey lists messages of the errors wrapped by the logged error,
+	// in the same order as ExceptionCauseTypeKey
+	ExceptionCauseMessageKey = attribute.Key("exception.cause.message")
+)
+
+// exceptionKeys are attribute keys of a single logged error
+type exceptionKeys struct {
+	message, typ, stacktrace, causeType, causeMessage attribute.Key
+}
+
+// conventionalExceptionKeys are the semantic convention keys of errors logged with a conventional key
+var conventionalExceptionKeys = exceptionKeys{
+	message:      semconv.ExceptionMessageKey,
+	typ:          semconv.ExceptionTypeKey,
+	stacktrace:   semconv.ExceptionStacktraceKey,
+	causeType:    ExceptionCauseTypeKey,
+	causeMessage: ExceptionCauseMessageKey,
+}
+
+// isConventionalErrorKey reports whether the error attribute key is "err" or "error" outside any group
+func isConventionalErrorKey(key string) bool {
+	return key == "err" || key == "error"
+}
+
+// errorExceptionKeys returns exception.* keys for the conventional error keys, so errors can be grouped by type,
+// other errors keep their key, e.g. "db.err.message", and don't overwrite each other
+func errorExceptionKeys(key string) exceptionKeys {
+	if isConventionalErrorKey(key) {
+		return conventionalExceptionKeys
+	}
+	return exceptionKeys{
+		message:      attribute.Key(key + ".message"),
+		typ:          attribute.Key(key + ".type"),
+		stacktrace:   attribute.Key(key + ".stacktrace"),
+		causeType:    attribute.Key(key + ".cause.type"),
+		causeMessage: attribute.Key(key + ".cause.message"),
+	}
+}
+
+// appendException appends exception attributes of err logged with the key to dst:
+// message, type with the Go type name of the error,
+// stacktrace when the error or one of its causes carries a stack trace,
+// and cause.* with the wrapped errors.
+// Errors with "err" or "error" key outside groups use exception.* semantic convention keys,
+// other errors use the key with the group prefix in place of "exception".
+func appendException(dst []attribute.KeyValue, key string, err error) []attribute.KeyValue {
+	keys := errorExceptionKeys(key)
+	dst = append(dst,
+		keys.message.String(err.Error()),
+		keys.typ.String(errorType(err)),
+	)
+
+	stackTrace, hasStackTrace := errorStackTrace(err)
+	var causeTypes, causeMessages []string
+
+	queue := unwrapError(err)
+	for len(queue) > 0 && len(causeTypes) < maxExceptionCauses {
+		cause := queue[0]
+		queue = append(queue[1:], unwrapError(cause)...)
+
+		causeTypes = append(causeTypes, errorType(cause))
+		causeMessages = append(causeMessages, cause.Error())
+		// the deepest stack trace points to the origin of the error
+		if causeStackTrace, ok := errorStackTrace(cause); ok {
+			stackTrace, hasStackTrace = causeStackTrace, true
+		}
+	}
+
+	if hasStackTrace {
+		dst = append(dst, keys.stacktrace.String(stackTrace))
+	}
+	if len(causeTypes) > 0 {
+		dst = append(dst,
+			keys.causeType.StringSlice(causeTypes),
+			keys.causeMessage.StringSlice(causeMessages),
+		)
+	}
+	return dst
+}
+
+// unwrapError returns errors wrapped by err with Unwrap() error or Unwrap() []error, e.g. by errors.Join,
+// in a newly allocated slice the caller can modify
+func unwrapError(err error) []error {
+	var causes []error
+	switch e := err.(type) {
+	case interface{ Unwrap() error }:
+		causes = []error{e.Unwrap()}
+	case interface{ Unwrap() []error }:
+		causes = e.Unwrap()
+	}
+
+	// a new slice, the one returned by Unwrap belongs to the error and must not be modified
+	result := make([]error, 0, len(causes))
+	for _, cause := range causes {
+		if !isNil(cause) {
+			result = append(result, cause)
+		}
+	}
+	return result
+}
+
+// errorType returns the Go type name of the error, e.g. "*fs.PathError"
+func errorType(err error) string {
+	return reflect.TypeOf(err).String()
+}
+
+// errorStackTrace returns the stack trace of errors with StackTrace method, like errors of github.com/pkg/errors,
+// formatted with %+v
+func errorStackTrace(err error) (string, bool) {
+	method := reflect.ValueOf(err).MethodByName("StackTrace")
+	if !method.IsValid() || method.Type().NumIn() != 0 || method.Type().NumOut() != 1 {
+		return "", false
+	}
+	stackTrace := fmt.Sprintf("%+v", method.Call(nil)[0].Interface())
+	return stackTrace, stackTrace != ""
+}
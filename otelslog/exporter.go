@@ -0,0 +1,125 @@
+/*
+Copyright Agoda Services Co.,Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package otelslog
+
+import (
+	"context"
+	"errors"
+	otel "github.com/agoda-com/opentelemetry-logs-go/logs"
+	sdk "github.com/agoda-com/opentelemetry-logs-go/sdk/logs"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"log/slog"
+	"sync/atomic"
+)
+
+// Exporter is a LogRecordExporter writing OpenTelemetry log records through a slog.Handler,
+// so records of every bridge (otelzap, otelzerolog, otelslog) can share one local output format
+//
+//	provider := sdk.NewLoggerProvider(sdk.WithSyncer(otelslog.NewExporter(slog.NewJSONHandler(os.Stdout, nil))))
+type Exporter struct {
+	handler slog.Handler
+	stopped atomic.Bool
+}
+
+var _ sdk.LogRecordExporter = &Exporter{}
+
+// NewExporter creates Exporter writing records to handler
+func NewExporter(handler slog.Handler) *Exporter {
+	return &Exporter{handler: handler}
+}
+
+// Export writes every record of the batch the handler is enabled for.
+// Errors of the handler don't stop the batch, they are joined and returned at the end.
+func (e *Exporter) Export(ctx context.Context, batch []sdk.ReadableLogRecord) error {
+	if e.stopped.Load() {
+		return nil
+	}
+
+	var errs []error
+	for _, r := range batch {
+		if err := ctx.Err(); err != nil {
+			return errors.Join(append(errs, err)...)
+		}
+		level := slogLevel(r.SeverityNumber())
+		if !e.handler.Enabled(ctx, level) {
+			continue
+		}
+		if err := e.handler.Handle(ctx, slogRecord(r, level)); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Shutdown stops the exporter, records exported afterwards are dropped
+func (e *Exporter) Shutdown(ctx context.Context) error {
+	e.stopped.Store(true)
+	return ctx.Err()
+}
+
+// slogLevel reverses DefaultLevelMapper, records without severity are logged at Info
+func slogLevel(severity *otel.SeverityNumber) slog.Level {
+	if severity == nil || *severity == otel.UNSPECIFIED {
+		return slog.LevelInfo
+	}
+	return severityLevel(*severity)
+}
+
+func slogRecord(r sdk.ReadableLogRecord, level slog.Level) slog.Record {
+	t := r.ObservedTimestamp()
+	if r.Timestamp() != nil {
+		t = *r.Timestamp()
+	}
+	var message string
+	if r.Body() != nil {
+		message = *r.Body()
+	}
+
+	record := slog.NewRecord(t, level, message, 0)
+	if attributes := r.Attributes(); attributes != nil {
+		for _, kv := range *attributes {
+			record.AddAttrs(slogAttr(kv))
+		}
+	}
+	if scope := r.InstrumentationScope(); scope != nil && scope.Name != "" {
+		record.AddAttrs(slog.String(string(semconv.OTelScopeNameKey), scope.Name))
+	}
+	if traceID := r.TraceId(); traceID != nil && traceID.IsValid() {
+		record.AddAttrs(slog.String(traceIDKey, traceID.String()))
+	}
+	if spanID := r.SpanId(); spanID != nil && spanID.IsValid() {
+		record.AddAttrs(slog.String(spanIDKey, spanID.String()))
+	}
+	return record
+}
+
+// slogAttr converts OpenTelemetry attribute into slog Attr, slices are kept as typed slices
+func slogAttr(kv attribute.KeyValue) slog.Attr {
+	key := string(kv.Key)
+	switch kv.Value.Type() {
+	case attribute.BOOL:
+		return slog.Bool(key, kv.Value.AsBool())
+	case attribute.INT64:
+		return slog.Int64(key, kv.Value.AsInt64())
+	case attribute.FLOAT64:
+		return slog.Float64(key, kv.Value.AsFloat64())
+	case attribute.STRING:
+		return slog.String(key, kv.Value.AsString())
+	}
+	return slog.Any(key, kv.Value.AsInterface())
+}
@@ -0,0 +1,55 @@
+package otelslog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+	"time"
+
+	sdk "github.com/agoda-com/opentelemetry-logs-go/sdk/logs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExporter(t *testing.T) {
+	var buf bytes.Buffer
+	exporter := NewExporter(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	loggerProvider := sdk.NewLoggerProvider(sdk.WithSyncer(exporter))
+
+	timestamp := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	logger := slog.New(NewOtelHandler(loggerProvider, &HandlerOptions{Level: LevelTrace}))
+	record := slog.NewRecord(timestamp, slog.LevelWarn, "hello", 0)
+	record.AddAttrs(slog.String("user", "alice"), slog.Int("count", 3), slog.Any("tags", []string{"a", "b"}))
+	assert.NoError(t, logger.Handler().Handle(testSpanContext(), record))
+
+	// below the level of the slog handler
+	logger.Log(context.Background(), LevelTrace, "dropped")
+
+	var line map[string]any
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &line))
+	assert.Equal(t, map[string]any{
+		"time":            "2024-01-02T03:04:05Z",
+		"level":           "WARN",
+		"msg":             "hello",
+		"user":            "alice",
+		"count":           float64(3),
+		"tags":            []any{"a", "b"},
+		"otel.scope.name": instrumentationName,
+		"trace_id":        "0102030405060708090a0b0c0d0e0f10",
+		"span_id":         "0102030405060708",
+	}, line)
+
+	assert.NoError(t, exporter.Shutdown(context.Background()))
+	buf.Reset()
+	logger.Info("after shutdown")
+	assert.Empty(t, buf.String())
+}
+
+func TestSlogLevel(t *testing.T) {
+	for _, level := range []slog.Level{LevelTrace, slog.LevelDebug, slog.LevelInfo, slog.LevelWarn, slog.LevelError, LevelFatal} {
+		severity, _ := DefaultLevelMapper(level)
+		assert.Equal(t, level, slogLevel(&severity))
+	}
+	assert.Equal(t, slog.LevelInfo, slogLevel(nil))
+}
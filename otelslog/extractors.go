@@ -0,0 +1,84 @@
+/*
+Copyright Agoda Services Co.,Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package otelslog
+
+import (
+	"context"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"net"
+	"net/http"
+	"runtime/pprof"
+)
+
+// PprofLabels is a context extractor that adds profiler labels of the context, set with pprof.Do or pprof.WithLabels
+func PprofLabels(ctx context.Context) []attribute.KeyValue {
+	var attributes []attribute.KeyValue
+	pprof.ForLabels(ctx, func(key, value string) bool {
+		attributes = append(attributes, attribute.String(key, value))
+		return true
+	})
+	return attributes
+}
+
+type httpRequestKey struct{}
+
+// HTTPMiddleware stores information about the incoming request in its context,
+// so the HTTPRequest extractor can add it to every record logged with that context
+func HTTPMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), httpRequestKey{}, httpRequestAttributes(r))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// HTTPRequest is a context extractor that adds http.request.method, url.scheme, url.path, server.address,
+// client.address and user_agent.original of the request stored by HTTPMiddleware
+func HTTPRequest(ctx context.Context) []attribute.KeyValue {
+	attributes, _ := ctx.Value(httpRequestKey{}).([]attribute.KeyValue)
+	return attributes
+}
+
+// httpRequestAttributes are computed once per request, extractor returns them as is
+func httpRequestAttributes(r *http.Request) []attribute.KeyValue {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+
+	attributes := []attribute.KeyValue{
+		semconv.HTTPRequestMethodKey.String(r.Method),
+		semconv.URLScheme(scheme),
+		semconv.URLPath(r.URL.Path),
+		semconv.ServerAddress(hostOnly(r.Host)),
+	}
+	if r.RemoteAddr != "" {
+		attributes = append(attributes, semconv.ClientAddress(hostOnly(r.RemoteAddr)))
+	}
+	if userAgent := r.UserAgent(); userAgent != "" {
+		attributes = append(attributes, semconv.UserAgentOriginal(userAgent))
+	}
+	return attributes
+}
+
+// hostOnly strips port from the address if present
+func hostOnly(address string) string {
+	if host, _, err := net.SplitHostPort(address); err == nil {
+		return host
+	}
+	return address
+}
@@ -0,0 +1,56 @@
+package otelslog
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"runtime/pprof"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type tenantKey struct{}
+
+func TestOtelHandler_ContextExtractors(t *testing.T) {
+	loggerProvider, exporter := newMemoryProvider()
+
+	logger := slog.New(NewOtelHandler(loggerProvider, &HandlerOptions{
+		ContextExtractors: []func(context.Context) []attribute.KeyValue{
+			func(ctx context.Context) []attribute.KeyValue {
+				if tenant, ok := ctx.Value(tenantKey{}).(string); ok {
+					return []attribute.KeyValue{attribute.String("tenant.id", tenant)}
+				}
+				return nil
+			},
+			PprofLabels,
+			HTTPRequest,
+		},
+	}))
+
+	handler := HTTPMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), tenantKey{}, "acme")
+		pprof.Do(ctx, pprof.Labels("worker", "payments"), func(ctx context.Context) {
+			logger.InfoContext(ctx, "hello")
+		})
+	}))
+	request := httptest.NewRequest(http.MethodPost, "http://example.com:8080/api/payments?id=1", nil)
+	request.Header.Set("User-Agent", "test-agent")
+	request.RemoteAddr = "10.0.0.1:51234"
+	handler.ServeHTTP(httptest.NewRecorder(), request)
+
+	records := exporter.Records()
+	assert.Len(t, records, 1)
+	assert.Equal(t, map[attribute.Key]attribute.Value{
+		"tenant.id":           attribute.StringValue("acme"),
+		"worker":              attribute.StringValue("payments"),
+		"http.request.method": attribute.StringValue("POST"),
+		"url.scheme":          attribute.StringValue("http"),
+		"url.path":            attribute.StringValue("/api/payments"),
+		"server.address":      attribute.StringValue("example.com"),
+		"client.address":      attribute.StringValue("10.0.0.1"),
+		"user_agent.original": attribute.StringValue("test-agent"),
+	}, attributeMap(records[0]))
+}
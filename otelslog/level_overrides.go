@@ -0,0 +1,140 @@
+/*
+Copyright Agoda Services Co.,Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package otelslog
+
+import (
+	"log/slog"
+	"path"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// LevelOverrides is a table of minimum levels keyed by logger name patterns.
+// The logger name of a handler is the dot separated path of the groups opened with WithGroup, e.g. "db.query".
+//
+// Patterns use path.Match syntax, "*" matches any sequence of characters including dots,
+// so "db.*" matches every group nested in "db" and "db" matches the group itself.
+// When several patterns match, the longest one wins.
+//
+// LevelOverrides is safe for concurrent use and can be updated while handlers are logging,
+// lookups never take a lock. The zero value has no overrides.
+type LevelOverrides struct {
+	// mu serializes writers, readers only load the current table
+	mu    sync.Mutex
+	table atomic.Pointer[levelTable]
+}
+
+type levelRule struct {
+	pattern string
+	level   slog.Leveler
+}
+
+// levelTable is immutable snapshot of overrides
+type levelTable struct {
+	// rules sorted by pattern length, longest first
+	rules []levelRule
+	// resolved caches matching rule per logger name, nil when no rule matches
+	resolved sync.Map // map[string]slog.Leveler
+}
+
+// NewLevelOverrides creates LevelOverrides with the given pattern to level table
+func NewLevelOverrides(levels map[string]slog.Leveler) (*LevelOverrides, error) {
+	overrides := &LevelOverrides{}
+	for pattern, level := range levels {
+		if err := overrides.Set(pattern, level); err != nil {
+			return nil, err
+		}
+	}
+	return overrides, nil
+}
+
+// Set sets minimum level for logger names matching pattern.
+// The level is read on every lookup, so slog.LevelVar can be used to change it without calling Set again.
+// The only possible returned error is path.ErrBadPattern.
+func (l *LevelOverrides) Set(pattern string, level slog.Leveler) error {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return err
+	}
+
+	l.update(func(rules []levelRule) []levelRule {
+		for i, rule := range rules {
+			if rule.pattern == pattern {
+				rules[i].level = level
+				return rules
+			}
+		}
+		return append(rules, levelRule{pattern: pattern, level: level})
+	})
+	return nil
+}
+
+// Delete removes override for pattern
+func (l *LevelOverrides) Delete(pattern string) {
+	l.update(func(rules []levelRule) []levelRule {
+		for i, rule := range rules {
+			if rule.pattern == pattern {
+				return append(rules[:i], rules[i+1:]...)
+			}
+		}
+		return rules
+	})
+}
+
+// Lookup returns minimum level for the logger name, ok is false when no override matches
+func (l *LevelOverrides) Lookup(name string) (level slog.Level, ok bool) {
+	table := l.table.Load()
+	if table == nil {
+		return 0, false
+	}
+
+	if leveler, found := table.resolved.Load(name); found {
+		if leveler == nil {
+			return 0, false
+		}
+		return leveler.(slog.Leveler).Level(), true
+	}
+
+	var leveler slog.Leveler
+	for _, rule := range table.rules {
+		if matched, _ := path.Match(rule.pattern, name); matched {
+			leveler = rule.level
+			break
+		}
+	}
+	table.resolved.Store(name, leveler)
+	if leveler == nil {
+		return 0, false
+	}
+	return leveler.Level(), true
+}
+
+// update replaces the table with a copy modified by fn
+func (l *LevelOverrides) update(fn func(rules []levelRule) []levelRule) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var rules []levelRule
+	if current := l.table.Load(); current != nil {
+		rules = append(rules, current.rules...)
+	}
+	rules = fn(rules)
+	sort.SliceStable(rules, func(i, j int) bool {
+		return len(rules[i].pattern) > len(rules[j].pattern)
+	})
+	l.table.Store(&levelTable{rules: rules})
+}
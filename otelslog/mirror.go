@@ -0,0 +1,234 @@
+/*
+Copyright Agoda Services Co.,Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package otelslog
+
+import (
+	"encoding/json"
+	"fmt"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"log/slog"
+	"strconv"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MirrorFormat is the format of records mirrored to HandlerOptions.Mirror
+type MirrorFormat int
+
+const (
+	// MirrorText writes records as key=value pairs, like slog.TextHandler
+	MirrorText MirrorFormat = iota
+	// MirrorJSON writes records as JSON objects, like slog.JSONHandler
+	MirrorJSON
+)
+
+const (
+	traceIDKey = "trace_id"
+	spanIDKey  = "span_id"
+)
+
+// ANSI escape codes used to color the level of mirrored text lines
+const (
+	colorReset = "\x1b[0m"
+	colorDebug = "\x1b[36m"
+	colorInfo  = "\x1b[32m"
+	colorWarn  = "\x1b[33m"
+	colorError = "\x1b[31m"
+)
+
+// mirrorStamp is the time layout of mirrored text lines
+const mirrorStamp = "2006-01-02T15:04:05.000Z07:00"
+
+// mirrorRecord is everything printed for a single record
+type mirrorRecord struct {
+	time       time.Time
+	level      slog.Level
+	levelText  *string
+	message    *string
+	traceID    *trace.TraceID
+	spanID     *trace.SpanID
+	attributes []attribute.KeyValue
+}
+
+const maxPooledMirrorBuffer = 64 << 10
+
+var mirrorBufferPool = sync.Pool{
+	New: func() any {
+		buf := make([]byte, 0, 1024)
+		return &buf
+	},
+}
+
+// mirrorEnabled reports whether records of level are printed to the mirror writer
+func (o otelHandler) mirrorEnabled(level slog.Level) bool {
+	if o.w == nil {
+		return false
+	}
+	if o.opts.MirrorLevel != nil {
+		return o.levelEnabled(level, o.opts.MirrorLevel.Level())
+	}
+	return o.levelEnabled(level, o.minLevel())
+}
+
+// mirror writes the record to the mirror writer as a single line
+func (o otelHandler) mirror(r mirrorRecord) error {
+	buf := mirrorBufferPool.Get().(*[]byte)
+	line := (*buf)[:0]
+	if o.opts.MirrorFormat == MirrorJSON {
+		line = appendJSONLine(line, r)
+	} else {
+		line = appendTextLine(line, r, o.opts.MirrorColor)
+	}
+
+	o.mu.Lock()
+	_, err := o.w.Write(line)
+	o.mu.Unlock()
+
+	// don't keep memory of exceptionally long lines alive
+	if cap(line) <= maxPooledMirrorBuffer {
+		*buf = line
+		mirrorBufferPool.Put(buf)
+	}
+	return err
+}
+
+func appendTextLine(line []byte, r mirrorRecord, color bool) []byte {
+	if !r.time.IsZero() {
+		line = r.time.AppendFormat(line, mirrorStamp)
+		line = append(line, ' ')
+	}
+	if r.levelText != nil {
+		if color {
+			line = append(line, levelColor(r.level)...)
+			line = append(line, *r.levelText...)
+			line = append(line, colorReset...)
+		} else {
+			line = append(line, *r.levelText...)
+		}
+		line = append(line, ' ')
+	}
+	if r.message != nil {
+		line = append(line, *r.message...)
+	}
+	for _, kv := range r.attributes {
+		line = append(line, ' ')
+		line = append(line, kv.Key...)
+		line = append(line, '=')
+		if kv.Value.Type() == attribute.STRING {
+			line = appendTextString(line, kv.Value.AsString())
+		} else {
+			line = append(line, kv.Value.Emit()...)
+		}
+	}
+	if r.traceID != nil {
+		line = append(line, " "+traceIDKey+"="...)
+		line = append(line, r.traceID.String()...)
+	}
+	if r.spanID != nil {
+		line = append(line, " "+spanIDKey+"="...)
+		line = append(line, r.spanID.String()...)
+	}
+	return append(line, '\n')
+}
+
+// appendTextString quotes the value only when it can't be read back unambiguously
+func appendTextString(line []byte, s string) []byte {
+	if needsQuoting(s) {
+		return strconv.AppendQuote(line, s)
+	}
+	return append(line, s...)
+}
+
+func needsQuoting(s string) bool {
+	if len(s) == 0 {
+		return true
+	}
+	for _, r := range s {
+		if r == '=' || r == '"' || r == utf8.RuneError || unicode.IsSpace(r) || !unicode.IsPrint(r) {
+			return true
+		}
+	}
+	return false
+}
+
+func appendJSONLine(line []byte, r mirrorRecord) []byte {
+	line = append(line, '{')
+	if !r.time.IsZero() {
+		line = appendJSONKey(line, slog.TimeKey)
+		line = append(line, '"')
+		line = r.time.AppendFormat(line, time.RFC3339Nano)
+		line = append(line, '"', ',')
+	}
+	if r.levelText != nil {
+		line = appendJSONKey(line, slog.LevelKey)
+		line = appendJSONValue(line, *r.levelText)
+		line = append(line, ',')
+	}
+	if r.message != nil {
+		line = appendJSONKey(line, slog.MessageKey)
+		line = appendJSONValue(line, *r.message)
+		line = append(line, ',')
+	}
+	for _, kv := range r.attributes {
+		line = appendJSONKey(line, string(kv.Key))
+		line = appendJSONValue(line, kv.Value.AsInterface())
+		line = append(line, ',')
+	}
+	if r.traceID != nil {
+		line = appendJSONKey(line, traceIDKey)
+		line = appendJSONValue(line, r.traceID.String())
+		line = append(line, ',')
+	}
+	if r.spanID != nil {
+		line = appendJSONKey(line, spanIDKey)
+		line = appendJSONValue(line, r.spanID.String())
+		line = append(line, ',')
+	}
+	if line[len(line)-1] == ',' {
+		line = line[:len(line)-1]
+	}
+	return append(line, '}', '\n')
+}
+
+func appendJSONKey(line []byte, key string) []byte {
+	line = appendJSONValue(line, key)
+	return append(line, ':')
+}
+
+func appendJSONValue(line []byte, value any) []byte {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		// only non-finite floats can't be encoded, print them as strings
+		encoded, _ = json.Marshal(fmt.Sprint(value))
+	}
+	return append(line, encoded...)
+}
+
+func levelColor(level slog.Level) string {
+	switch {
+	case level >= slog.LevelError:
+		return colorError
+	case level >= slog.LevelWarn:
+		return colorWarn
+	case level >= slog.LevelInfo:
+		return colorInfo
+	}
+	return colorDebug
+}
@@ -20,13 +20,14 @@ import (
 	"context"
 	otel "github.com/agoda-com/opentelemetry-logs-go/logs"
 	"go.opentelemetry.io/otel/attribute"
-	"go.opentelemetry.io/otel/baggage"
 	"go.opentelemetry.io/otel/sdk/instrumentation"
 	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
 	"go.opentelemetry.io/otel/trace"
 	"io"
 	"log/slog"
+	"strings"
 	"sync"
+	"time"
 )
 
 const (
@@ -41,17 +42,105 @@ type OtelHandler struct {
 // HandlerOptions are options for a OtelHandler.
 // A zero HandlerOptions consists entirely of default values.
 type HandlerOptions struct {
-	Level slog.Leveler
+	Level      slog.Leveler
 	AddBaggage bool
+
+	// BaggageAllow lists patterns (path.Match syntax) of baggage member keys added with AddBaggage.
+	// All members are added when empty.
+	BaggageAllow []string
+	// BaggageDeny lists patterns of baggage member keys never added, it takes precedence over BaggageAllow.
+	BaggageDeny []string
+	// BaggagePrefix is prepended to the keys of baggage attributes, e.g. "baggage.",
+	// so baggage can't collide with the attributes of the service.
+	BaggagePrefix string
+	// BaggageMaxMembers limits the number of baggage members added to a record, unlimited when zero.
+	BaggageMaxMembers int
+	// BaggageMaxValueLength truncates baggage values longer than the given number of bytes, unlimited when zero.
+	BaggageMaxValueLength int
+	// BaggageProperties adds properties of baggage members as "<key>.<property>" attributes,
+	// properties without value are added as true.
+	BaggageProperties bool
+
+	// ContextExtractors are called with the context of every record
+	// and their attributes are added to the record, e.g. tenant or request IDs stored in the context.
+	// See PprofLabels and HTTPRequest for the built-in extractors.
+	ContextExtractors []func(context.Context) []attribute.KeyValue
+
+	// LevelOverrides overrides Level for handlers whose group path matches one of the patterns,
+	// e.g. debug logs for "db.*" groups while everything else stays at Level.
+	LevelOverrides *LevelOverrides
+
+	// LevelMapper converts slog levels into severity number and text of the records,
+	// DefaultLevelMapper when nil. When set, levels are compared to Level by their severity numbers.
+	LevelMapper LevelMapper
+
+	// AddSource causes the handler to resolve the source code position of the log statement
+	// into code.filepath, code.lineno, code.function and code.namespace attributes.
+	AddSource bool
+	// TrimSourcePath reports code.filepath relative to the root of its module
+	// instead of the absolute path on the build machine.
+	TrimSourcePath bool
+
+	// ReplaceAttr is called to rewrite each non-group attribute before it is exported.
+	// The attribute's value has been resolved (see slog.Value.Resolve).
+	// If ReplaceAttr returns a zero Attr, the attribute is discarded.
+	//
+	// The built-in severity text and body are passed to ReplaceAttr with keys slog.LevelKey and slog.MessageKey
+	// and nil groups. Other arguments follow the semantics of slog.HandlerOptions.ReplaceAttr:
+	// groups lists the group names of the attribute from the outermost, both the ones opened by WithGroup
+	// and the ones the attribute is nested in.
+	ReplaceAttr func(groups []string, a slog.Attr) slog.Attr
+
+	// Mirror, when set, receives every record as a human-readable line in addition to the export,
+	// e.g. os.Stderr for local development. Lines include trace_id and span_id of the record.
+	Mirror io.Writer
+	// MirrorFormat is the format of mirrored lines, MirrorText by default.
+	MirrorFormat MirrorFormat
+	// MirrorColor colors the level of MirrorText lines by severity with ANSI escape codes.
+	MirrorColor bool
+	// MirrorLevel is the minimum level of mirrored records, independent of Level.
+	// If nil, the minimum level of the export is used.
+	MirrorLevel slog.Leveler
+
+	// Clock returns the time the handler observed the record, exported as ObservedTimestamp.
+	// time.Now is used when nil.
+	Clock func() time.Time
 }
 
 type otelHandler struct {
-	logger      otel.Logger
+	logger   otel.Logger
+	provider otel.LoggerProvider
+	scope    *instrumentation.Scope
+	// global holds the logger of the global provider for scope, nil when provider is set
+	global      *globalLoggerCache
 	opts        HandlerOptions
 	groupPrefix string
-	attrs       []slog.Attr
-	mu          *sync.Mutex
-	w           io.Writer
+	groups      []string
+	// attrs are bound with WithAttrs, already converted and prefixed; never modified after creation
+	attrs []attribute.KeyValue
+	mu    *sync.Mutex
+	w     io.Writer
+}
+
+// attributePool holds buffers for attributes of a single record
+var attributePool = sync.Pool{
+	New: func() any {
+		buf := make([]attribute.KeyValue, 0, 32)
+		return &buf
+	},
+}
+
+// maxPooledAttributes limits capacity of buffers returned to the pool, so a single huge record
+// doesn't keep its memory alive
+const maxPooledAttributes = 1024
+
+func releaseAttributes(buf *[]attribute.KeyValue) {
+	if cap(*buf) > maxPooledAttributes {
+		return
+	}
+	clear(*buf)
+	*buf = (*buf)[:0]
+	attributePool.Put(buf)
 }
 
 // compilation time verification handler implement interface
@@ -64,14 +153,57 @@ var instrumentationScope = instrumentation.Scope{
 }
 
 func (o otelHandler) Enabled(ctx context.Context, level slog.Level) bool {
-	minLevel := slog.LevelInfo
+	return o.levelEnabled(level, o.minLevel()) || o.mirrorEnabled(level)
+}
+
+func (o otelHandler) minLevel() slog.Level {
+	if o.opts.LevelOverrides != nil {
+		if name := o.name(); name != "" {
+			if level, ok := o.opts.LevelOverrides.Lookup(name); ok {
+				return level
+			}
+		}
+		if o.scope != &instrumentationScope {
+			if level, ok := o.opts.LevelOverrides.Lookup(o.scope.Name); ok {
+				return level
+			}
+		}
+	}
 	if o.opts.Level != nil {
-		minLevel = o.opts.Level.Level()
+		return o.opts.Level.Level()
 	}
-	return level >= minLevel
+	return slog.LevelInfo
+}
+
+// otelLogger returns the logger records are emitted to,
+// handlers created without LoggerProvider use the current global one
+func (o otelHandler) otelLogger() otel.Logger {
+	if o.provider == nil {
+		return o.global.logger()
+	}
+	return o.logger
+}
+
+func (o otelHandler) now() time.Time {
+	if o.opts.Clock != nil {
+		return o.opts.Clock()
+	}
+	return time.Now()
+}
+
+// name returns the logger name used for level overrides, the dot separated path of groups
+func (o otelHandler) name() string {
+	return strings.TrimSuffix(o.groupPrefix, ".")
 }
 
 func (o otelHandler) Handle(ctx context.Context, record slog.Record) error {
+	observedTime := o.now()
+
+	// zero time means the record has no time
+	var timestamp *time.Time
+	if !record.Time.IsZero() {
+		timestamp = &record.Time
+	}
 
 	spanContext := trace.SpanFromContext(ctx).SpanContext()
 	var traceID *trace.TraceID = nil
@@ -85,96 +217,189 @@ func (o otelHandler) Handle(ctx context.Context, record slog.Record) error {
 		spanID = &sid
 		traceFlags = &tf
 	}
-	levelString := record.Level.String()
-	severity := otel.SeverityNumber(int(record.Level.Level()) + 9)
+	severity, levelString := o.severity(record.Level)
+	severityText := &levelString
+	body := &record.Message
+
+	if o.opts.ReplaceAttr != nil {
+		severityText = o.replaceLevel(record.Level)
+		body = o.replaceBuiltin(slog.String(slog.MessageKey, record.Message))
+	}
 
-	var attributes []attribute.KeyValue
+	// per-record attributes are collected into pooled buffer and then copied
+	// together with bound attributes into the slice owned by the log record
+	buf := attributePool.Get().(*[]attribute.KeyValue)
+	defer releaseAttributes(buf)
+	attrs := *buf
 
 	if o.opts.AddBaggage {
-		b := baggage.FromContext(ctx)
-		// Iterate over baggage items and add them to log attributes
-		for _, i := range b.Members() {
-			attributes = append(attributes, attribute.String(i.Key(), i.Value()))
-		}
+		attrs = o.appendBaggage(attrs, ctx)
 	}
-	for _, attr := range o.attrs {
-		attributes = append(attributes, otelAttribute(attr)...)
+	for _, extract := range o.opts.ContextExtractors {
+		attrs = append(attrs, extract(ctx)...)
 	}
-
+	contextLen := len(attrs)
 
 	record.Attrs(func(attr slog.Attr) bool {
-		attributes = append(attributes, otelAttribute(withGroupPrefix(o.groupPrefix, attr))...)
+		if o.opts.ReplaceAttr != nil {
+			if attr = o.replaceAttr(o.groups, attr); attr.Equal(slog.Attr{}) {
+				return true
+			}
+		}
+		attrs = appendOtelAttribute(attrs, o.groupPrefix, attr, 0)
 		return true
 	})
 
+	if o.opts.AddSource {
+		attrs = append(attrs, sourceAttributes(record.PC, o.opts.TrimSourcePath)...)
+	}
+	*buf = attrs
+
+	attributes := make([]attribute.KeyValue, 0, len(o.attrs)+len(attrs))
+	attributes = append(attributes, attrs[:contextLen]...)
+	attributes = append(attributes, o.attrs...)
+	attributes = append(attributes, attrs[contextLen:]...)
+
 	lrc := otel.LogRecordConfig{
-		Timestamp:            &record.Time,
-		ObservedTimestamp:    record.Time,
+		Timestamp:            timestamp,
+		ObservedTimestamp:    observedTime,
 		TraceId:              traceID,
 		SpanId:               spanID,
 		TraceFlags:           traceFlags,
-		SeverityText:         &levelString,
+		SeverityText:         severityText,
 		SeverityNumber:       &severity,
-		Body:                 &record.Message,
+		Body:                 body,
 		Resource:             nil,
-		InstrumentationScope: &instrumentationScope,
+		InstrumentationScope: o.scope,
 		Attributes:           &attributes,
 	}
 
-	r := otel.NewLogRecord(lrc)
-	o.logger.Emit(r)
+	// the record may be enabled only for the mirror
+	if o.levelEnabled(record.Level, o.minLevel()) {
+		r := otel.NewLogRecord(lrc)
+		o.otelLogger().Emit(r)
+	}
+
+	if o.mirrorEnabled(record.Level) {
+		return o.mirror(mirrorRecord{
+			time:       record.Time,
+			level:      record.Level,
+			levelText:  severityText,
+			message:    body,
+			traceID:    traceID,
+			spanID:     spanID,
+			attributes: attributes,
+		})
+	}
 	return nil
 }
 
-func withGroupPrefix(groupPrefix string, attr slog.Attr) slog.Attr {
-	if groupPrefix != "" {
-		attr.Key = groupPrefix + attr.Key
+// replaceAttr applies ReplaceAttr to attr or, for group values, to every member of the group
+// A zero Attr is returned when the attribute has to be discarded
+func (o otelHandler) replaceAttr(groups []string, attr slog.Attr) slog.Attr {
+	attr.Value = attr.Value.Resolve()
+	if attr.Value.Kind() != slog.KindGroup {
+		attr = o.opts.ReplaceAttr(groups, attr)
+		attr.Value = attr.Value.Resolve()
+		return attr
+	}
+
+	if attr.Key != "" {
+		groups = append(groups[:len(groups):len(groups)], attr.Key)
+	}
+	members := attr.Value.Group()
+	replaced := make([]slog.Attr, 0, len(members))
+	for _, member := range members {
+		if member = o.replaceAttr(groups, member); !member.Equal(slog.Attr{}) {
+			replaced = append(replaced, member)
+		}
+	}
+	if len(replaced) == 0 {
+		return slog.Attr{}
 	}
+	attr.Value = slog.GroupValue(replaced...)
 	return attr
 }
 
+// replaceLevel applies ReplaceAttr to the severity text, nil is returned when it was discarded
+func (o otelHandler) replaceLevel(level slog.Level) *string {
+	attr := o.opts.ReplaceAttr(nil, slog.Any(slog.LevelKey, level))
+	if attr.Equal(slog.Attr{}) {
+		return nil
+	}
+	// keep the text of the level mapper unless the level was replaced with a different value
+	if replaced, ok := attr.Value.Any().(slog.Level); ok {
+		_, text := o.severity(replaced)
+		return &text
+	}
+	text := attr.Value.Resolve().String()
+	return &text
+}
+
+// replaceBuiltin applies ReplaceAttr to built-in attribute, nil is returned when it was discarded
+func (o otelHandler) replaceBuiltin(attr slog.Attr) *string {
+	attr = o.opts.ReplaceAttr(nil, attr)
+	if attr.Equal(slog.Attr{}) {
+		return nil
+	}
+	value := attr.Value.Resolve().String()
+	return &value
+}
+
 func (o otelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
-	for i, attr := range attrs {
-		attrs[i] = withGroupPrefix(o.groupPrefix, attr)
+	if len(attrs) == 0 {
+		return o
 	}
 
-	return &otelHandler{
-		logger:      o.logger,
-		opts:        o.opts,
-		groupPrefix: o.groupPrefix,
-		attrs:       append(o.attrs, attrs...),
+	// always copy, so handlers derived from the same parent never share the backing array
+	bound := make([]attribute.KeyValue, len(o.attrs), len(o.attrs)+len(attrs))
+	copy(bound, o.attrs)
+	for _, attr := range attrs {
+		if o.opts.ReplaceAttr != nil {
+			if attr = o.replaceAttr(o.groups, attr); attr.Equal(slog.Attr{}) {
+				continue
+			}
+		}
+		bound = appendOtelAttribute(bound, o.groupPrefix, attr, 0)
 	}
+
+	handler := o
+	handler.attrs = bound
+	return &handler
 }
 
 func (o otelHandler) WithGroup(name string) slog.Handler {
 	if name == "" {
 		return o
 	}
-	prefix := name + "."
-	if o.groupPrefix != "" {
-		prefix = o.groupPrefix + prefix
-	}
 
-	return &otelHandler{
-		logger:      o.logger,
-		opts:        o.opts,
-		attrs:       o.attrs,
-		groupPrefix: prefix,
-	}
+	handler := o
+	handler.groupPrefix = o.groupPrefix + name + "."
+	handler.groups = append(o.groups[:len(o.groups):len(o.groups)], name)
+	return &handler
 }
 
 // NewOtelHandler creates a OtelHandler that writes to otlp,
 // using the given options.
 // If opts is nil, the default options are used.
+// If loggerProvider is nil, the handler follows the global LoggerProvider, see New.
 func NewOtelHandler(loggerProvider otel.LoggerProvider, opts *HandlerOptions) *OtelHandler {
-	logger := loggerProvider.Logger(
-		instrumentationScope.Name,
-		otel.WithInstrumentationVersion(instrumentationScope.Version),
-	)
+	if opts == nil {
+		opts = &HandlerOptions{}
+	}
+	handler := otelHandler{
+		provider: loggerProvider,
+		scope:    &instrumentationScope,
+		opts:     *opts,
+		mu:       &sync.Mutex{},
+		w:        opts.Mirror,
+	}
+	if loggerProvider != nil {
+		handler.logger = cachedLogger(loggerProvider, instrumentationScope)
+	} else {
+		handler.global = newGlobalLoggerCache(instrumentationScope)
+	}
 	return &OtelHandler{
-		otelHandler: otelHandler{
-			logger: logger,
-			opts:   *opts,
-		},
+		otelHandler: handler,
 	}
 }
@@ -4,16 +4,26 @@ import (
 	"bytes"
 	"context"
 	"go.opentelemetry.io/otel/baggage"
+	"io"
 	"log/slog"
 	"os"
+	"path"
+	"runtime"
+	"strings"
+	"sync"
 	"testing"
+	"testing/slogtest"
+	"time"
 
+	otellogs "github.com/agoda-com/opentelemetry-logs-go"
 	"github.com/agoda-com/opentelemetry-logs-go/exporters/stdout/stdoutlogs"
 	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
 
 	sdk "github.com/agoda-com/opentelemetry-logs-go/sdk/logs"
 	"go.opentelemetry.io/otel/sdk/resource"
 	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // configure common attributes for all logs
@@ -69,3 +79,515 @@ func TestNewOtelHandler(t *testing.T) {
 	assert.Contains(t, actual, "INFO hello slog [scopeInfo: github.com/agoda-com/otelslog:0.2.0] {host.name=")
 	assert.Contains(t, actual, "service.name=otelslog-example, service.version=1.0.0, baggage.key=true, first=value1, group1.second=value2, group1.group2.myKey=myValue, group1.group2.myGroup.groupKey=groupValue}")
 }
+
+// memoryExporter keeps exported log records in memory for assertions
+type memoryExporter struct {
+	mu      sync.Mutex
+	records []sdk.ReadableLogRecord
+}
+
+func (e *memoryExporter) Export(ctx context.Context, batch []sdk.ReadableLogRecord) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.records = append(e.records, batch...)
+	return nil
+}
+
+func (e *memoryExporter) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+func (e *memoryExporter) Records() []sdk.ReadableLogRecord {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]sdk.ReadableLogRecord(nil), e.records...)
+}
+
+// newMemoryProvider creates logger provider which synchronously exports into memory
+func newMemoryProvider() (*sdk.LoggerProvider, *memoryExporter) {
+	exporter := &memoryExporter{}
+	return sdk.NewLoggerProvider(sdk.WithSyncer(exporter)), exporter
+}
+
+// attributeMap converts record attributes into map for assertions
+func attributeMap(record sdk.ReadableLogRecord) map[attribute.Key]attribute.Value {
+	result := map[attribute.Key]attribute.Value{}
+	if record.Attributes() != nil {
+		for _, kv := range *record.Attributes() {
+			result[kv.Key] = kv.Value
+		}
+	}
+	return result
+}
+
+func TestOtelHandler_AddSource(t *testing.T) {
+	loggerProvider, exporter := newMemoryProvider()
+
+	logger := slog.New(NewOtelHandler(loggerProvider, &HandlerOptions{
+		AddSource:      true,
+		TrimSourcePath: true,
+	}))
+
+	var line int
+	for i := 0; i < 2; i++ {
+		_, _, line, _ = runtime.Caller(0)
+		logger.Info("hello source")
+	}
+
+	records := exporter.Records()
+	assert.Len(t, records, 2)
+	for _, record := range records {
+		attributes := attributeMap(record)
+		assert.Equal(t, "otel_handler_test.go", attributes[semconv.CodeFilepathKey].AsString())
+		assert.Equal(t, int64(line+1), attributes[semconv.CodeLineNumberKey].AsInt64())
+		assert.Equal(t, "TestOtelHandler_AddSource", attributes[semconv.CodeFunctionKey].AsString())
+		assert.Equal(t, "github.com/agoda-com/opentelemetry-go/otelslog", attributes[semconv.CodeNamespaceKey].AsString())
+	}
+}
+
+func TestSplitFunctionName(t *testing.T) {
+	tests := []struct {
+		Input     string
+		Namespace string
+		Function  string
+	}{
+		{Input: "main.main", Namespace: "main", Function: "main"},
+		{Input: "github.com/org/repo/pkg.Func", Namespace: "github.com/org/repo/pkg", Function: "Func"},
+		{Input: "github.com/org/repo/pkg.(*Type).Method", Namespace: "github.com/org/repo/pkg.(*Type)", Function: "Method"},
+		{Input: "github.com/org/repo.v2/pkg.Func.func1", Namespace: "github.com/org/repo.v2/pkg.Func", Function: "func1"},
+		{Input: "pkg.F[...]", Namespace: "pkg", Function: "F"},
+		{Input: "pkg.F[...].func1", Namespace: "pkg.F", Function: "func1"},
+		{Input: "github.com/org/repo/pkg.(*Type[...]).Method", Namespace: "github.com/org/repo/pkg.(*Type)", Function: "Method"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Input, func(t *testing.T) {
+			namespace, function := splitFunctionName(test.Input)
+			assert.Equal(t, test.Namespace, namespace)
+			assert.Equal(t, test.Function, function)
+		})
+	}
+}
+
+func TestOtelHandler_ReplaceAttr(t *testing.T) {
+	loggerProvider, exporter := newMemoryProvider()
+
+	var seen [][]string
+	handler := NewOtelHandler(loggerProvider, &HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			switch a.Key {
+			case slog.LevelKey:
+				return slog.String(a.Key, "severity-"+a.Value.String())
+			case slog.MessageKey:
+				return slog.String(a.Key, "body-"+a.Value.String())
+			case "password":
+				return slog.Attr{}
+			case "user":
+				a.Key = "user.name"
+			}
+			seen = append(seen, append([]string{a.Key}, groups...))
+			return a
+		},
+	})
+
+	logger := slog.New(handler).
+		With(slog.String("user", "john"), slog.String("password", "secret")).
+		WithGroup("request")
+	logger.Info("hello", slog.Group("http", slog.String("password", "secret"), slog.Int("status", 200)))
+
+	records := exporter.Records()
+	assert.Len(t, records, 1)
+	assert.Equal(t, "severity-INFO", *records[0].SeverityText())
+	assert.Equal(t, "body-hello", *records[0].Body())
+	assert.Equal(t, map[attribute.Key]attribute.Value{
+		"user.name":           attribute.StringValue("john"),
+		"request.http.status": attribute.Int64Value(200),
+	}, attributeMap(records[0]))
+	assert.Equal(t, [][]string{{"user.name"}, {"status", "request", "http"}}, seen)
+}
+
+func TestOtelHandler_ReplaceAttr_DropBuiltin(t *testing.T) {
+	loggerProvider, exporter := newMemoryProvider()
+
+	logger := slog.New(NewOtelHandler(loggerProvider, &HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.MessageKey || a.Key == slog.LevelKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+	logger.Warn("hello")
+
+	records := exporter.Records()
+	assert.Len(t, records, 1)
+	assert.Nil(t, records[0].SeverityText())
+	assert.Nil(t, records[0].Body())
+}
+
+// slogtestResult converts exported record back into the nested map form expected by testing/slogtest
+func slogtestResult(record sdk.ReadableLogRecord) map[string]any {
+	result := map[string]any{}
+	if ts := record.Timestamp(); ts != nil && !ts.IsZero() {
+		result[slog.TimeKey] = *ts
+	}
+	if record.SeverityText() != nil {
+		result[slog.LevelKey] = *record.SeverityText()
+	}
+	if record.Body() != nil {
+		result[slog.MessageKey] = *record.Body()
+	}
+	if record.Attributes() == nil {
+		return result
+	}
+	for _, kv := range *record.Attributes() {
+		path := strings.Split(string(kv.Key), ".")
+		group := result
+		for _, name := range path[:len(path)-1] {
+			next, ok := group[name].(map[string]any)
+			if !ok {
+				next = map[string]any{}
+				group[name] = next
+			}
+			group = next
+		}
+		group[path[len(path)-1]] = kv.Value.AsInterface()
+	}
+	return result
+}
+
+func TestOtelHandler_Slogtest(t *testing.T) {
+	loggerProvider, exporter := newMemoryProvider()
+
+	handler := NewOtelHandler(loggerProvider, &HandlerOptions{})
+
+	err := slogtest.TestHandler(handler, func() []map[string]any {
+		var results []map[string]any
+		for _, record := range exporter.Records() {
+			results = append(results, slogtestResult(record))
+		}
+		return results
+	})
+	assert.NoError(t, err)
+}
+
+func TestOtelHandler_WithAttrsDoesNotShareState(t *testing.T) {
+	loggerProvider, exporter := newMemoryProvider()
+
+	attrs := []slog.Attr{slog.String("a", "1")}
+	parent := NewOtelHandler(loggerProvider, &HandlerOptions{}).WithGroup("g").WithAttrs(attrs)
+	first := parent.WithAttrs([]slog.Attr{slog.String("b", "first")})
+	second := parent.WithAttrs([]slog.Attr{slog.String("b", "second")})
+
+	assert.Equal(t, "a", attrs[0].Key)
+
+	slog.New(first).Info("first")
+	slog.New(second).Info("second")
+
+	records := exporter.Records()
+	assert.Len(t, records, 2)
+	assert.Equal(t, "first", attributeMap(records[0])["g.b"].AsString())
+	assert.Equal(t, "second", attributeMap(records[1])["g.b"].AsString())
+}
+
+// benchmarkAttrs are bound to the logger, mimicking request-scoped logger
+var benchmarkAttrs = []slog.Attr{
+	slog.String("service", "payments"),
+	slog.String("request.id", "0f7f1a5e-2b7c-4a51-a43e-6c1b6d3f1c2a"),
+	slog.String("tenant", "acme"),
+	slog.Int("user.id", 42),
+	slog.Bool("beta", true),
+	slog.String("region", "ap-southeast-1"),
+	slog.String("host", "payments-7c9d7f9b5-x2x7k"),
+	slog.Int("attempt", 1),
+	slog.Float64("ratio", 0.25),
+	slog.Duration("timeout", time.Second),
+	slog.String("route", "/api/v1/payments"),
+	slog.String("method", "POST"),
+	slog.String("client", "ios"),
+	slog.String("version", "1.4.0"),
+	slog.Group("db", slog.String("system", "mysql"), slog.String("name", "payments")),
+}
+
+func benchmarkHandler(b *testing.B, handler slog.Handler) {
+	logger := slog.New(handler.WithAttrs(benchmarkAttrs)).WithGroup("call")
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		logger.LogAttrs(ctx, slog.LevelInfo, "payment processed",
+			slog.String("payment.id", "pay_123"),
+			slog.Int("amount", 1000),
+			slog.String("currency", "THB"),
+			slog.Duration("elapsed", 15*time.Millisecond),
+			slog.Bool("captured", true),
+		)
+	}
+}
+
+func BenchmarkOtelHandler(b *testing.B) {
+	// provider without processors measures the handler only
+	benchmarkHandler(b, NewOtelHandler(sdk.NewLoggerProvider(), &HandlerOptions{}))
+}
+
+func BenchmarkJSONHandler(b *testing.B) {
+	benchmarkHandler(b, slog.NewJSONHandler(io.Discard, nil))
+}
+
+func TestOtelHandler_LevelOverrides(t *testing.T) {
+	ctx := context.Background()
+	defaultLevel := &slog.LevelVar{}
+	overrides, err := NewLevelOverrides(map[string]slog.Leveler{
+		"db":   slog.LevelDebug,
+		"db.*": slog.LevelDebug,
+	})
+	assert.NoError(t, err)
+
+	handler := NewOtelHandler(sdk.NewLoggerProvider(), &HandlerOptions{
+		Level:          defaultLevel,
+		LevelOverrides: overrides,
+	})
+	db := handler.WithGroup("db")
+	query := db.WithGroup("query")
+	http := handler.WithGroup("http")
+
+	assert.False(t, handler.Enabled(ctx, slog.LevelDebug))
+	assert.True(t, db.Enabled(ctx, slog.LevelDebug))
+	assert.True(t, query.Enabled(ctx, slog.LevelDebug))
+	assert.False(t, http.Enabled(ctx, slog.LevelDebug))
+
+	// overrides and default level can be changed at runtime
+	assert.NoError(t, overrides.Set("db.query", slog.LevelWarn))
+	overrides.Delete("db")
+	defaultLevel.Set(slog.LevelDebug)
+
+	assert.True(t, handler.Enabled(ctx, slog.LevelDebug))
+	assert.True(t, db.Enabled(ctx, slog.LevelDebug))
+	assert.False(t, query.Enabled(ctx, slog.LevelInfo))
+	assert.True(t, http.Enabled(ctx, slog.LevelDebug))
+
+	assert.ErrorIs(t, overrides.Set("[", slog.LevelInfo), path.ErrBadPattern)
+}
+
+func testSpanContext() context.Context {
+	traceID, _ := trace.TraceIDFromHex("0102030405060708090a0b0c0d0e0f10")
+	spanID, _ := trace.SpanIDFromHex("0102030405060708")
+	return trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	}))
+}
+
+func TestOtelHandler_Mirror(t *testing.T) {
+	loggerProvider, exporter := newMemoryProvider()
+	var text, json bytes.Buffer
+	recordTime := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	for _, mirror := range []struct {
+		writer io.Writer
+		format MirrorFormat
+	}{{&text, MirrorText}, {&json, MirrorJSON}} {
+		handler := NewOtelHandler(loggerProvider, &HandlerOptions{
+			Level:        slog.LevelWarn,
+			Mirror:       mirror.writer,
+			MirrorFormat: mirror.format,
+			MirrorLevel:  slog.LevelDebug,
+		}).WithAttrs([]slog.Attr{slog.String("user", "john doe")})
+
+		for _, level := range []slog.Level{slog.LevelDebug, slog.LevelWarn} {
+			record := slog.NewRecord(recordTime, level, "hello", 0)
+			record.AddAttrs(slog.Int("count", 1))
+			assert.True(t, handler.Enabled(context.Background(), level))
+			assert.NoError(t, handler.Handle(testSpanContext(), record))
+		}
+	}
+
+	// only warnings are exported, everything is mirrored
+	assert.Len(t, exporter.Records(), 2)
+	assert.Equal(t, `2024-01-02T03:04:05.000Z DEBUG hello user="john doe" count=1 trace_id=0102030405060708090a0b0c0d0e0f10 span_id=0102030405060708
+2024-01-02T03:04:05.000Z WARN hello user="john doe" count=1 trace_id=0102030405060708090a0b0c0d0e0f10 span_id=0102030405060708
+`, text.String())
+	assert.Equal(t, `{"time":"2024-01-02T03:04:05Z","level":"DEBUG","msg":"hello","user":"john doe","count":1,"trace_id":"0102030405060708090a0b0c0d0e0f10","span_id":"0102030405060708"}
+{"time":"2024-01-02T03:04:05Z","level":"WARN","msg":"hello","user":"john doe","count":1,"trace_id":"0102030405060708090a0b0c0d0e0f10","span_id":"0102030405060708"}
+`, json.String())
+}
+
+func TestOtelHandler_MirrorColor(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewOtelHandler(sdk.NewLoggerProvider(), &HandlerOptions{
+		Mirror:      &buf,
+		MirrorColor: true,
+	}))
+
+	logger.Error("failed")
+
+	assert.Contains(t, buf.String(), " \x1b[31mERROR\x1b[0m failed\n")
+}
+
+func TestOtelHandler_BaggageFiltering(t *testing.T) {
+	loggerProvider, exporter := newMemoryProvider()
+
+	logger := slog.New(NewOtelHandler(loggerProvider, &HandlerOptions{
+		AddBaggage:            true,
+		BaggageAllow:          []string{"tenant.*", "service.name", "user.*"},
+		BaggageDeny:           []string{"user.email"},
+		BaggagePrefix:         "baggage.",
+		BaggageMaxMembers:     3,
+		BaggageMaxValueLength: 6,
+		BaggageProperties:     true,
+	}))
+
+	property, _ := baggage.NewKeyValueProperty("source", "gateway")
+	flag, _ := baggage.NewKeyProperty("verified")
+	tenant, _ := baggage.NewMember("tenant.id", "acme", property, flag)
+	service, _ := baggage.NewMember("service.name", "attacker")
+	email, _ := baggage.NewMember("user.email", "john@example.com")
+	user, _ := baggage.NewMember("user.id", "1234567890")
+	zone, _ := baggage.NewMember("zone", "th")
+	extra, _ := baggage.NewMember("user.name", "john")
+	bag, _ := baggage.New(tenant, service, email, user, zone, extra)
+
+	logger.InfoContext(baggage.ContextWithBaggage(context.Background(), bag), "hello")
+
+	records := exporter.Records()
+	assert.Len(t, records, 1)
+	// user.name is dropped by BaggageMaxMembers as members are added in key order
+	assert.Equal(t, map[attribute.Key]attribute.Value{
+		"baggage.service.name":       attribute.StringValue("attack"),
+		"baggage.tenant.id":          attribute.StringValue("acme"),
+		"baggage.tenant.id.source":   attribute.StringValue("gatewa"),
+		"baggage.tenant.id.verified": attribute.BoolValue(true),
+		"baggage.user.id":            attribute.StringValue("123456"),
+	}, attributeMap(records[0]))
+}
+
+func TestOtelHandler_WithScope(t *testing.T) {
+	loggerProvider, exporter := newMemoryProvider()
+	overrides, _ := NewLevelOverrides(map[string]slog.Leveler{"payments/*": slog.LevelDebug})
+
+	handler := NewOtelHandler(loggerProvider, &HandlerOptions{LevelOverrides: overrides})
+	repository := handler.WithScope("payments/repository", "1.4.0")
+
+	assert.False(t, handler.Enabled(context.Background(), slog.LevelDebug))
+	assert.True(t, repository.Enabled(context.Background(), slog.LevelDebug))
+	assert.Same(t, repository.logger, handler.WithScope("payments/repository", "1.4.0").logger)
+
+	slog.New(repository).With(slog.String("table", "payments")).Debug("query")
+	slog.New(handler).Info("request")
+
+	records := exporter.Records()
+	assert.Len(t, records, 2)
+	assert.Equal(t, "payments/repository", records[0].InstrumentationScope().Name)
+	assert.Equal(t, "1.4.0", records[0].InstrumentationScope().Version)
+	assert.Equal(t, "payments", attributeMap(records[0])["table"].AsString())
+	assert.Equal(t, instrumentationName, records[1].InstrumentationScope().Name)
+}
+
+func TestOtelHandler_GlobalLoggerCache(t *testing.T) {
+	previous := otellogs.GetLoggerProvider()
+	defer otellogs.SetLoggerProvider(previous)
+
+	handler := New()
+	first, _ := newMemoryProvider()
+	otellogs.SetLoggerProvider(first)
+	logger := handler.otelLogger()
+	assert.Same(t, logger, handler.WithAttrs([]slog.Attr{slog.Int("a", 1)}).(*otelHandler).otelLogger())
+
+	// handlers derived per request and other root handlers reuse the cached logger
+	scoped := handler.WithScope("payments", "1.0.0").otelLogger()
+	assert.Same(t, scoped, handler.WithScope("payments", "1.0.0").otelLogger())
+	assert.Same(t, scoped, New().WithScope("payments", "1.0.0").otelLogger())
+	assert.Same(t, logger, New().otelLogger())
+
+	second, _ := newMemoryProvider()
+	otellogs.SetLoggerProvider(second)
+	assert.NotSame(t, logger, handler.otelLogger())
+
+	// loggers of the replaced global provider are dropped
+	_, ok := loggers.Load(loggerKey{provider: first, scope: instrumentationScope})
+	assert.False(t, ok)
+}
+
+func TestOtelHandler_LoggerCache(t *testing.T) {
+	loggerProvider, _ := newMemoryProvider()
+
+	first := NewOtelHandler(loggerProvider, nil)
+	second := NewOtelHandler(loggerProvider, &HandlerOptions{Level: slog.LevelDebug})
+	assert.Same(t, first.logger, second.logger)
+	assert.Same(t, first.WithScope("payments", "1.0.0").logger, second.WithScope("payments", "1.0.0").logger)
+
+	other, _ := newMemoryProvider()
+	assert.NotSame(t, first.logger, NewOtelHandler(other, nil).logger)
+}
+
+func TestNewOtelHandler_NilOptions(t *testing.T) {
+	loggerProvider, exporter := newMemoryProvider()
+
+	slog.New(NewOtelHandler(loggerProvider, nil)).Info("hello")
+
+	assert.Len(t, exporter.Records(), 1)
+}
+
+func TestNew_GlobalLoggerProvider(t *testing.T) {
+	previous := otellogs.GetLoggerProvider()
+	defer otellogs.SetLoggerProvider(previous)
+
+	// handler created before the application configures the SDK
+	logger := slog.New(New(WithLevel(slog.LevelWarn)).WithScope("payments", "1.0.0"))
+	logger.Warn("dropped")
+
+	first, firstExporter := newMemoryProvider()
+	otellogs.SetLoggerProvider(first)
+	logger.Info("filtered")
+	logger.Warn("first")
+
+	second, secondExporter := newMemoryProvider()
+	otellogs.SetLoggerProvider(second)
+	logger.Warn("second")
+
+	assert.Len(t, firstExporter.Records(), 1)
+	assert.Equal(t, "first", *firstExporter.Records()[0].Body())
+	assert.Len(t, secondExporter.Records(), 1)
+	assert.Equal(t, "second", *secondExporter.Records()[0].Body())
+	assert.Equal(t, "payments", secondExporter.Records()[0].InstrumentationScope().Name)
+}
+
+func TestNew_WithLoggerProvider(t *testing.T) {
+	loggerProvider, exporter := newMemoryProvider()
+	var buf bytes.Buffer
+
+	logger := slog.New(New(
+		WithLoggerProvider(loggerProvider),
+		WithHandlerOptions(HandlerOptions{Level: slog.LevelDebug}),
+		WithAddSource(true),
+		WithMirror(&buf, MirrorText),
+	))
+	logger.Debug("hello")
+
+	records := exporter.Records()
+	assert.Len(t, records, 1)
+	assert.Equal(t, "otel_handler_test.go", attributeMap(records[0])[semconv.CodeFilepathKey].AsString())
+	assert.Contains(t, buf.String(), "DEBUG hello")
+}
+
+func TestOtelHandler_Timestamps(t *testing.T) {
+	loggerProvider, exporter := newMemoryProvider()
+	recordTime := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	observedTime := recordTime.Add(time.Second)
+
+	handler := New(WithLoggerProvider(loggerProvider), WithClock(func() time.Time {
+		return observedTime
+	}))
+
+	assert.NoError(t, handler.Handle(context.Background(), slog.NewRecord(recordTime, slog.LevelInfo, "with time", 0)))
+	assert.NoError(t, handler.Handle(context.Background(), slog.NewRecord(time.Time{}, slog.LevelInfo, "without time", 0)))
+
+	records := exporter.Records()
+	assert.Len(t, records, 2)
+	assert.Equal(t, recordTime, *records[0].Timestamp())
+	assert.Equal(t, observedTime, records[0].ObservedTimestamp())
+	assert.Nil(t, records[1].Timestamp())
+	assert.Equal(t, observedTime, records[1].ObservedTimestamp())
+}
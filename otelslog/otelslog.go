@@ -15,3 +15,95 @@ limitations under the License.
 */
 
 package otelslog
+
+import (
+	otel "github.com/agoda-com/opentelemetry-logs-go/logs"
+	"io"
+	"log/slog"
+	"time"
+)
+
+// New creates OtelHandler configured with functional options.
+// Without WithLoggerProvider the handler follows the global LoggerProvider of opentelemetry-logs-go,
+// so libraries can create handlers at init time and records are exported through whatever provider
+// the application sets with SetLoggerProvider later, including providers swapped at runtime.
+func New(opts ...Option) *OtelHandler {
+	c := &config{}
+	for _, apply := range opts {
+		apply(c)
+	}
+	return NewOtelHandler(c.loggerProvider, &c.handlerOptions)
+}
+
+// Option is a function that applies an option to OtelHandler created with New
+type Option func(c *config)
+
+type config struct {
+	loggerProvider otel.LoggerProvider
+	handlerOptions HandlerOptions
+}
+
+// WithLoggerProvider sets the LoggerProvider records are exported to, instead of the global one
+func WithLoggerProvider(loggerProvider otel.LoggerProvider) Option {
+	return Option(func(c *config) {
+		c.loggerProvider = loggerProvider
+	})
+}
+
+// WithHandlerOptions replaces all handler options, options applied after it modify the given ones
+func WithHandlerOptions(opts HandlerOptions) Option {
+	return Option(func(c *config) {
+		c.handlerOptions = opts
+	})
+}
+
+// WithLevel sets the minimum level of exported records
+func WithLevel(level slog.Leveler) Option {
+	return Option(func(c *config) {
+		c.handlerOptions.Level = level
+	})
+}
+
+// WithAddSource adds code.* attributes of the log statement, see HandlerOptions.AddSource
+func WithAddSource(trimSourcePath bool) Option {
+	return Option(func(c *config) {
+		c.handlerOptions.AddSource = true
+		c.handlerOptions.TrimSourcePath = trimSourcePath
+	})
+}
+
+// WithAddBaggage adds members of the context baggage as attributes, see HandlerOptions.AddBaggage
+func WithAddBaggage() Option {
+	return Option(func(c *config) {
+		c.handlerOptions.AddBaggage = true
+	})
+}
+
+// WithReplaceAttr sets the hook to rewrite attributes, see HandlerOptions.ReplaceAttr
+func WithReplaceAttr(replaceAttr func(groups []string, a slog.Attr) slog.Attr) Option {
+	return Option(func(c *config) {
+		c.handlerOptions.ReplaceAttr = replaceAttr
+	})
+}
+
+// WithLevelMapper sets the conversion of slog levels into severities, see HandlerOptions.LevelMapper
+func WithLevelMapper(levelMapper LevelMapper) Option {
+	return Option(func(c *config) {
+		c.handlerOptions.LevelMapper = levelMapper
+	})
+}
+
+// WithClock sets the source of ObservedTimestamp of records, see HandlerOptions.Clock
+func WithClock(clock func() time.Time) Option {
+	return Option(func(c *config) {
+		c.handlerOptions.Clock = clock
+	})
+}
+
+// WithMirror prints every record to w in the given format, see HandlerOptions.Mirror
+func WithMirror(w io.Writer, format MirrorFormat) Option {
+	return Option(func(c *config) {
+		c.handlerOptions.Mirror = w
+		c.handlerOptions.MirrorFormat = format
+	})
+}
@@ -0,0 +1,138 @@
+/*
+Copyright Agoda Services Co.,Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package otelslog
+
+import (
+	otellogs "github.com/agoda-com/opentelemetry-logs-go"
+	otel "github.com/agoda-com/opentelemetry-logs-go/logs"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+// loggerKey identifies the logger of a provider for an instrumentation scope
+type loggerKey struct {
+	provider otel.LoggerProvider
+	scope    instrumentation.Scope
+}
+
+// loggers caches loggers per provider and instrumentation scope, so every handler of a provider and scope,
+// e.g. derived by WithScope on each request, exports through the same logger
+var loggers sync.Map // map[loggerKey]otel.Logger
+
+// globalProvider is the last global provider loggers were created for
+var globalProvider atomic.Pointer[globalLogger]
+
+// cachedLogger returns logger of the provider for the instrumentation scope,
+// providers which can't be used as map keys get a new logger on every call
+func cachedLogger(provider otel.LoggerProvider, scope instrumentation.Scope) otel.Logger {
+	if !reflect.TypeOf(provider).Comparable() {
+		return newScopedLogger(provider, scope)
+	}
+	key := loggerKey{provider: provider, scope: scope}
+	if logger, ok := loggers.Load(key); ok {
+		return logger.(otel.Logger)
+	}
+	logger, _ := loggers.LoadOrStore(key, newScopedLogger(provider, scope))
+	return logger.(otel.Logger)
+}
+
+// replaceGlobalProvider drops cached loggers of the previous global provider, so providers replaced
+// by otellogs.SetLoggerProvider are not kept alive. Handlers with explicit providers keep their own loggers.
+func replaceGlobalProvider(provider otel.LoggerProvider) {
+	previous := globalProvider.Swap(&globalLogger{provider: provider})
+	if previous == nil || previous.provider == provider {
+		return
+	}
+	loggers.Range(func(key, _ any) bool {
+		if key.(loggerKey).provider == previous.provider {
+			loggers.Delete(key)
+		}
+		return true
+	})
+}
+
+// globalLogger is the logger of the global provider it was created with
+type globalLogger struct {
+	provider otel.LoggerProvider
+	logger   otel.Logger
+	// comparable is false for providers which panic on ==, their loggers are never reused
+	comparable bool
+}
+
+// globalLoggerCache holds the logger of the global provider for a single instrumentation scope,
+// it's replaced when the application sets another global provider
+type globalLoggerCache struct {
+	scope   instrumentation.Scope
+	current atomic.Pointer[globalLogger]
+}
+
+func newGlobalLoggerCache(scope instrumentation.Scope) *globalLoggerCache {
+	return &globalLoggerCache{scope: scope}
+}
+
+// logger returns logger of the current global provider
+func (c *globalLoggerCache) logger() otel.Logger {
+	provider := otellogs.GetLoggerProvider()
+	if cached := c.current.Load(); cached != nil && cached.comparable && cached.provider == provider {
+		return cached.logger
+	}
+	cached := &globalLogger{
+		provider:   provider,
+		comparable: reflect.TypeOf(provider).Comparable(),
+	}
+	if cached.comparable {
+		if current := globalProvider.Load(); current == nil || current.provider != provider {
+			replaceGlobalProvider(provider)
+		}
+	}
+	cached.logger = cachedLogger(provider, c.scope)
+	c.current.Store(cached)
+	return cached.logger
+}
+
+func newScopedLogger(provider otel.LoggerProvider, scope instrumentation.Scope) otel.Logger {
+	return provider.Logger(
+		scope.Name,
+		otel.WithInstrumentationVersion(scope.Version),
+		otel.WithSchemaURL(scope.SchemaURL),
+	)
+}
+
+// WithScope returns a handler which exports records with the instrumentation scope of the calling component,
+// e.g. WithScope("payments/repository", "1.4.0"), instead of the scope of otelslog.
+// Attributes, groups and options of the handler are kept.
+//
+// Loggers are cached per LoggerProvider and scope, so deriving handlers for the same component is cheap.
+// The scope name is also matched by LevelOverrides when the group path of the handler is empty or doesn't match.
+func (h *OtelHandler) WithScope(name, version string) *OtelHandler {
+	scope := instrumentation.Scope{
+		Name:      name,
+		Version:   version,
+		SchemaURL: instrumentationScope.SchemaURL,
+	}
+
+	handler := h.otelHandler
+	handler.scope = &scope
+	if handler.provider != nil {
+		handler.logger = cachedLogger(handler.provider, scope)
+	} else {
+		handler.global = newGlobalLoggerCache(scope)
+	}
+	return &OtelHandler{otelHandler: handler}
+}
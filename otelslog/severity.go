@@ -0,0 +1,128 @@
+/*
+Copyright Agoda Services Co.,Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package otelslog
+
+import (
+	otel "github.com/agoda-com/opentelemetry-logs-go/logs"
+	"log/slog"
+	"strconv"
+)
+
+// Custom slog levels following the common convention, they are mapped to TRACE and FATAL severities
+const (
+	LevelTrace = slog.Level(-8)
+	LevelFatal = slog.Level(12)
+)
+
+// LevelMapper converts slog level into OpenTelemetry severity number and severity text.
+// The handler clamps returned severity number into the valid range of TRACE (1) to FATAL4 (24).
+type LevelMapper func(level slog.Level) (otel.SeverityNumber, string)
+
+// Severity is OpenTelemetry severity number and text of a slog level
+type Severity struct {
+	Number otel.SeverityNumber
+	Text   string
+}
+
+// DefaultLevelMapper keeps the distance between levels: slog.LevelInfo is INFO
+// and every level step is a severity step, so LevelTrace is TRACE and LevelFatal is FATAL.
+// The severity text of levels from slog.LevelDebug to slog.LevelError is level.String(), e.g. "INFO+2",
+// levels outside have the OpenTelemetry short name like SeverityLevelMapper, e.g. "FATAL" instead of "ERROR+4".
+func DefaultLevelMapper(level slog.Level) (otel.SeverityNumber, string) {
+	number := severityNumber(level)
+	if level < slog.LevelDebug || level > slog.LevelError {
+		return number, severityName(clampSeverity(number))
+	}
+	return number, level.String()
+}
+
+// SeverityLevelMapper maps levels like DefaultLevelMapper, but the severity text is the OpenTelemetry short name
+// of the severity, e.g. "TRACE" for LevelTrace, "ERROR2" for slog.LevelError+1 and "FATAL" for LevelFatal.
+func SeverityLevelMapper(level slog.Level) (otel.SeverityNumber, string) {
+	number := clampSeverity(severityNumber(level))
+	return number, severityName(number)
+}
+
+// NewLevelMapper creates LevelMapper with fixed severities of the given levels,
+// other levels are mapped by fallback or by SeverityLevelMapper when fallback is nil.
+func NewLevelMapper(severities map[slog.Level]Severity, fallback LevelMapper) LevelMapper {
+	if fallback == nil {
+		fallback = SeverityLevelMapper
+	}
+	// copy, so the caller can't change the mapping afterwards
+	table := make(map[slog.Level]Severity, len(severities))
+	for level, severity := range severities {
+		table[level] = severity
+	}
+	return func(level slog.Level) (otel.SeverityNumber, string) {
+		if severity, ok := table[level]; ok {
+			return severity.Number, severity.Text
+		}
+		return fallback(level)
+	}
+}
+
+// severityNumber maps slog.LevelInfo to INFO, keeping the distance between levels
+func severityNumber(level slog.Level) otel.SeverityNumber {
+	return otel.SeverityNumber(int(level) + int(otel.INFO))
+}
+
+// severityLevel reverses severityNumber
+func severityLevel(number otel.SeverityNumber) slog.Level {
+	return slog.Level(int(number) - int(otel.INFO))
+}
+
+var severityNames = [...]string{"TRACE", "DEBUG", "INFO", "WARN", "ERROR", "FATAL"}
+
+// severityName returns short name of a valid severity number, e.g. "INFO" or "WARN3"
+func severityName(number otel.SeverityNumber) string {
+	name := severityNames[(number-otel.TRACE)/4]
+	if step := (number - otel.TRACE) % 4; step > 0 {
+		name += strconv.Itoa(int(step) + 1)
+	}
+	return name
+}
+
+func clampSeverity(number otel.SeverityNumber) otel.SeverityNumber {
+	if number < otel.TRACE {
+		return otel.TRACE
+	}
+	if number > otel.FATAL4 {
+		return otel.FATAL4
+	}
+	return number
+}
+
+// severity maps the level with the configured LevelMapper
+func (o otelHandler) severity(level slog.Level) (otel.SeverityNumber, string) {
+	mapper := o.opts.LevelMapper
+	if mapper == nil {
+		mapper = DefaultLevelMapper
+	}
+	number, text := mapper(level)
+	return clampSeverity(number), text
+}
+
+// levelEnabled compares the level to the minimum level by their severity numbers
+func (o otelHandler) levelEnabled(level, minLevel slog.Level) bool {
+	if o.opts.LevelMapper == nil {
+		return level >= minLevel
+	}
+	number, _ := o.severity(level)
+	minNumber, _ := o.severity(minLevel)
+	return number >= minNumber
+}
@@ -0,0 +1,71 @@
+package otelslog
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"testing"
+
+	otel "github.com/agoda-com/opentelemetry-logs-go/logs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLevelMappers(t *testing.T) {
+	custom := NewLevelMapper(map[slog.Level]Severity{
+		slog.Level(-6): {Number: otel.DEBUG, Text: "VERBOSE"},
+	}, nil)
+
+	tests := []struct {
+		Mapper         LevelMapper
+		Level          slog.Level
+		ExpectedNumber otel.SeverityNumber
+		ExpectedText   string
+	}{
+		{Mapper: DefaultLevelMapper, Level: slog.LevelInfo, ExpectedNumber: otel.INFO, ExpectedText: "INFO"},
+		{Mapper: DefaultLevelMapper, Level: slog.LevelInfo + 2, ExpectedNumber: otel.INFO3, ExpectedText: "INFO+2"},
+		{Mapper: DefaultLevelMapper, Level: slog.LevelError, ExpectedNumber: otel.ERROR, ExpectedText: "ERROR"},
+		{Mapper: DefaultLevelMapper, Level: slog.LevelError + 1, ExpectedNumber: otel.ERROR2, ExpectedText: "ERROR2"},
+		{Mapper: DefaultLevelMapper, Level: slog.LevelError + 4, ExpectedNumber: otel.FATAL, ExpectedText: "FATAL"},
+		{Mapper: DefaultLevelMapper, Level: slog.Level(-20), ExpectedNumber: otel.TRACE, ExpectedText: "TRACE"},
+		{Mapper: DefaultLevelMapper, Level: slog.Level(100), ExpectedNumber: otel.FATAL4, ExpectedText: "FATAL4"},
+		{Mapper: SeverityLevelMapper, Level: LevelTrace, ExpectedNumber: otel.TRACE, ExpectedText: "TRACE"},
+		{Mapper: SeverityLevelMapper, Level: slog.LevelDebug + 1, ExpectedNumber: otel.DEBUG2, ExpectedText: "DEBUG2"},
+		{Mapper: SeverityLevelMapper, Level: slog.LevelWarn, ExpectedNumber: otel.WARN, ExpectedText: "WARN"},
+		{Mapper: SeverityLevelMapper, Level: slog.LevelError + 3, ExpectedNumber: otel.ERROR4, ExpectedText: "ERROR4"},
+		{Mapper: SeverityLevelMapper, Level: LevelFatal, ExpectedNumber: otel.FATAL, ExpectedText: "FATAL"},
+		{Mapper: SeverityLevelMapper, Level: slog.Level(-100), ExpectedNumber: otel.TRACE, ExpectedText: "TRACE"},
+		{Mapper: custom, Level: slog.Level(-6), ExpectedNumber: otel.DEBUG, ExpectedText: "VERBOSE"},
+		{Mapper: custom, Level: slog.LevelInfo, ExpectedNumber: otel.INFO, ExpectedText: "INFO"},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%s %d", test.ExpectedText, test.Level), func(t *testing.T) {
+			handler := otelHandler{opts: HandlerOptions{LevelMapper: test.Mapper}}
+			number, text := handler.severity(test.Level)
+			assert.Equal(t, test.ExpectedNumber, number)
+			assert.Equal(t, test.ExpectedText, text)
+		})
+	}
+}
+
+func TestOtelHandler_LevelMapper(t *testing.T) {
+	loggerProvider, exporter := newMemoryProvider()
+
+	logger := slog.New(NewOtelHandler(loggerProvider, &HandlerOptions{
+		Level:       LevelTrace,
+		LevelMapper: SeverityLevelMapper,
+	}))
+
+	// levels below LevelTrace have the same severity, so they are enabled too
+	assert.True(t, logger.Enabled(context.Background(), LevelTrace-4))
+
+	logger.Log(context.Background(), LevelTrace, "trace")
+	logger.Log(context.Background(), LevelFatal, "fatal")
+
+	records := exporter.Records()
+	assert.Len(t, records, 2)
+	assert.Equal(t, otel.TRACE, *records[0].SeverityNumber())
+	assert.Equal(t, "TRACE", *records[0].SeverityText())
+	assert.Equal(t, otel.FATAL, *records[1].SeverityNumber())
+	assert.Equal(t, "FATAL", *records[1].SeverityText())
+}
@@ -0,0 +1,143 @@
+/*
+Copyright Agoda Services Co.,Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package otelslog
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"path"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"sync"
+)
+
+type sourceKey struct {
+	pc   uintptr
+	trim bool
+}
+
+// sourceCache holds the code attributes resolved for every program counter,
+// so hot log sites pay for runtime.CallersFrames only once
+var sourceCache sync.Map // map[sourceKey][]attribute.KeyValue
+
+// sourceAttributes returns code.* semantic attributes for the log statement at pc
+// The returned slice is shared and must not be modified
+func sourceAttributes(pc uintptr, trim bool) []attribute.KeyValue {
+	if pc == 0 {
+		return nil
+	}
+	key := sourceKey{pc: pc, trim: trim}
+	if cached, ok := sourceCache.Load(key); ok {
+		return cached.([]attribute.KeyValue)
+	}
+
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	namespace, function := splitFunctionName(frame.Function)
+	file := frame.File
+	if trim {
+		file = trimSourcePath(frame.Function, file)
+	}
+
+	attributes := []attribute.KeyValue{
+		semconv.CodeFilepath(file),
+		semconv.CodeLineNumber(frame.Line),
+		semconv.CodeFunction(function),
+		semconv.CodeNamespace(namespace),
+	}
+	sourceCache.Store(key, attributes)
+	return attributes
+}
+
+// splitFunctionName splits fully qualified function name like "github.com/org/repo/pkg.(*Type).Method"
+// into namespace "github.com/org/repo/pkg.(*Type)" and function "Method".
+// Type arguments of generic functions and types like "pkg.Map[...]" are dropped, they may contain dots.
+func splitFunctionName(name string) (string, string) {
+	name = stripTypeArguments(name)
+	lastSlash := strings.LastIndex(name, "/")
+	lastDot := strings.LastIndex(name[lastSlash+1:], ".")
+	if lastDot < 0 {
+		return "", name
+	}
+	lastDot += lastSlash + 1
+	return name[:lastDot], name[lastDot+1:]
+}
+
+// stripTypeArguments removes bracketed type arguments, e.g. "pkg.(*Type[...]).Method" becomes "pkg.(*Type).Method"
+func stripTypeArguments(name string) string {
+	if !strings.Contains(name, "[") {
+		return name
+	}
+	var b strings.Builder
+	depth := 0
+	for _, r := range name {
+		switch {
+		case r == '[':
+			depth++
+		case r == ']' && depth > 0:
+			depth--
+		case depth == 0:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// packagePath returns import path of the package the function belongs to
+func packagePath(function string) string {
+	lastSlash := strings.LastIndex(function, "/")
+	dot := strings.Index(function[lastSlash+1:], ".")
+	if dot < 0 {
+		return function
+	}
+	return function[:lastSlash+1+dot]
+}
+
+var modulePaths = sync.OnceValue(func() []string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return nil
+	}
+	paths := []string{info.Main.Path}
+	for _, dep := range info.Deps {
+		paths = append(paths, dep.Path)
+	}
+	// longest module path first, so nested modules win over their parents
+	sort.Slice(paths, func(i, j int) bool {
+		return len(paths[i]) > len(paths[j])
+	})
+	return paths
+})
+
+// trimSourcePath converts absolute file path into path relative to the root of the module that contains function
+// The file path is returned unchanged when the module cannot be determined
+func trimSourcePath(function, file string) string {
+	pkg := packagePath(function)
+	for _, module := range modulePaths() {
+		if module == "" {
+			continue
+		}
+		if pkg == module {
+			return path.Base(file)
+		}
+		if strings.HasPrefix(pkg, module+"/") {
+			return path.Join(pkg[len(module)+1:], path.Base(file))
+		}
+	}
+	return file
+}
@@ -0,0 +1,71 @@
+/*
+Copyright Agoda Services Co.,Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package otelzap
+
+import (
+	"context"
+	"path"
+	"sort"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+)
+
+// contextAttributes returns baggage members and attributes of context extractors of the ctx
+func (c *otlpCore) contextAttributes(ctx context.Context) []attribute.KeyValue {
+	var attributes []attribute.KeyValue
+	if c.addBaggage {
+		attributes = c.appendBaggage(attributes, ctx)
+	}
+	for _, extractor := range c.contextExtractors {
+		attributes = append(attributes, extractor(ctx)...)
+	}
+	return attributes
+}
+
+// appendBaggage appends members of the context baggage allowed by the options to dst
+func (c *otlpCore) appendBaggage(dst []attribute.KeyValue, ctx context.Context) []attribute.KeyValue {
+	members := baggage.FromContext(ctx).Members()
+	// baggage keeps members in a map, sort them so attributes always have the same order
+	sort.Slice(members, func(i, j int) bool {
+		return members[i].Key() < members[j].Key()
+	})
+	for _, member := range members {
+		if c.baggageAllowed(member.Key()) {
+			dst = append(dst, attribute.String(member.Key(), member.Value()))
+		}
+	}
+	return dst
+}
+
+// baggageAllowed reports whether member key passes allow and deny patterns
+func (c *otlpCore) baggageAllowed(key string) bool {
+	for _, pattern := range c.baggageDeny {
+		if matched, _ := path.Match(pattern, key); matched {
+			return false
+		}
+	}
+	if len(c.baggageAllow) == 0 {
+		return true
+	}
+	for _, pattern := range c.baggageAllow {
+		if matched, _ := path.Match(pattern, key); matched {
+			return true
+		}
+	}
+	return false
+}
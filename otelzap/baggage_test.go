@@ -0,0 +1,53 @@
+package otelzap
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	"go.uber.org/zap"
+)
+
+type tenantKey struct{}
+
+func TestOtelCore_Baggage(t *testing.T) {
+	loggerProvider, exporter := newMemoryProvider()
+	tenant := func(ctx context.Context) []attribute.KeyValue {
+		if value, ok := ctx.Value(tenantKey{}).(string); ok {
+			return []attribute.KeyValue{attribute.String("tenant.id", value)}
+		}
+		return nil
+	}
+	logger := &Logger{zap.New(NewOtelCore(loggerProvider,
+		WithBaggageAllow("user.*", "session"),
+		WithBaggageDeny("user.secret"),
+		WithContextExtractors(tenant),
+	))}
+
+	bag, err := baggage.Parse("user.id=42,user.secret=hunter2,session=abc,other=x")
+	assert.NoError(t, err)
+	ctx := context.WithValue(baggage.ContextWithBaggage(testSpanContext(), bag), tenantKey{}, "acme")
+
+	logger.Ctx(ctx).Info("with", zap.String("key", "value"))
+	logger.InfoContext(ctx, "per call")
+	logger.Info("without context")
+
+	assert.Len(t, exporter.records, 3)
+	expected := map[string]interface{}{"user.id": "42", "session": "abc", "tenant.id": "acme"}
+	assert.Equal(t, map[string]interface{}{"user.id": "42", "session": "abc", "tenant.id": "acme", "key": "value"}, attributeMap(exporter.records[0]))
+	assert.Equal(t, expected, attributeMap(exporter.records[1]))
+	assert.Empty(t, attributeMap(exporter.records[2]))
+}
+
+func TestOtelCore_BaggageDisabled(t *testing.T) {
+	loggerProvider, exporter := newMemoryProvider()
+	logger := &Logger{zap.New(NewOtelCore(loggerProvider))}
+
+	bag, _ := baggage.Parse("user.id=42")
+	logger.InfoContext(baggage.ContextWithBaggage(context.Background(), bag), "message")
+
+	assert.Len(t, exporter.records, 1)
+	assert.Empty(t, attributeMap(exporter.records[0]))
+}
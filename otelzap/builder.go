@@ -0,0 +1,114 @@
+/*
+Copyright Agoda Services Co.,Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package otelzap
+
+import (
+	"time"
+
+	otel "github.com/agoda-com/opentelemetry-logs-go/logs"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// SamplingTarget selects the outputs sampled by WithSampling
+type SamplingTarget int
+
+const (
+	// SampleConsole samples only the console output, every entry is exported to OpenTelemetry
+	SampleConsole SamplingTarget = iota
+	// SampleAll samples entries before they reach both outputs, so both get the same entries
+	SampleAll
+)
+
+// NewProduction builds a logger writing JSON lines to stderr and exporting to OpenTelemetry at Info level and above,
+// with sampling of the console output, callers and stack traces of Error entries, like zap.NewProduction.
+// The logger replaces zap and otelzap globals, the returned func restores the previous ones.
+func NewProduction(loggerProvider otel.LoggerProvider, opts ...Option) (*Logger, func()) {
+	defaults := []Option{
+		WithSampling(&zap.SamplingConfig{Initial: 100, Thereafter: 100}, SampleConsole),
+	}
+	c := newConfig(loggerProvider, append(defaults, opts...))
+	encoder := zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig())
+	return c.build(encoder, zap.AddCaller(), zap.AddStacktrace(zap.ErrorLevel))
+}
+
+// NewDevelopment builds a logger writing human-friendly lines to stderr and exporting to OpenTelemetry
+// at Debug level and above, with callers and stack traces of Warn entries, like zap.NewDevelopment.
+// DPanic entries panic. The logger replaces zap and otelzap globals, the returned func restores the previous ones.
+func NewDevelopment(loggerProvider otel.LoggerProvider, opts ...Option) (*Logger, func()) {
+	defaults := []Option{
+		WithLevel(zap.DebugLevel),
+		WithConsoleLevel(zap.DebugLevel),
+	}
+	c := newConfig(loggerProvider, append(defaults, opts...))
+	encoder := zapcore.NewConsoleEncoder(zap.NewDevelopmentEncoderConfig())
+	return c.build(encoder, zap.Development(), zap.AddCaller(), zap.AddStacktrace(zap.WarnLevel))
+}
+
+// WithConsoleWriter sets the console output of NewProduction and NewDevelopment loggers, nil disables it
+func WithConsoleWriter(writer zapcore.WriteSyncer) Option {
+	return Option(func(c *config) {
+		c.consoleWriter = writer
+	})
+}
+
+// WithConsoleLevel sets the zapcore.LevelEnabler of the console output of NewProduction and NewDevelopment loggers,
+// independent of the level exported to OpenTelemetry set with WithLevel or WithLevelEnabler
+func WithConsoleLevel(levelEnabler zapcore.LevelEnabler) Option {
+	return Option(func(c *config) {
+		c.consoleEnabler = levelEnabler
+	})
+}
+
+// WithSampling sets sampling of NewProduction and NewDevelopment loggers, nil disables it.
+// The target selects whether only the console output or both outputs are sampled.
+func WithSampling(sampling *zap.SamplingConfig, target SamplingTarget) Option {
+	return Option(func(c *config) {
+		c.sampling = sampling
+		c.samplingTarget = target
+	})
+}
+
+// build creates the logger of both outputs and replaces globals with it
+func (c *config) build(encoder zapcore.Encoder, opts ...zap.Option) (*Logger, func()) {
+	var core zapcore.Core = c.otlpCore
+	if c.consoleWriter != nil {
+		console := zapcore.NewCore(encoder, c.consoleWriter, c.consoleEnabler)
+		if c.samplingTarget == SampleConsole {
+			console = c.sample(console)
+		}
+		core = zapcore.NewTee(console, core)
+	}
+	if c.samplingTarget == SampleAll {
+		core = c.sample(core)
+	}
+
+	logger := &Logger{zap.New(core, opts...)}
+	return logger, zap.ReplaceGlobals(logger.Logger)
+}
+
+// sample wraps core with the sampler of the config, the same way zap.Config.Build does
+func (c *config) sample(core zapcore.Core) zapcore.Core {
+	if c.sampling == nil {
+		return core
+	}
+	var opts []zapcore.SamplerOption
+	if c.sampling.Hook != nil {
+		opts = append(opts, zapcore.SamplerHook(c.sampling.Hook))
+	}
+	return zapcore.NewSamplerWithOptions(core, time.Second, c.sampling.Initial, c.sampling.Thereafter, opts...)
+}
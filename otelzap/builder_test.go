@@ -0,0 +1,91 @@
+package otelzap
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestNewProduction(t *testing.T) {
+	loggerProvider, exporter := newMemoryProvider()
+	var buf bytes.Buffer
+	previous := zap.L()
+
+	logger, undo := NewProduction(loggerProvider, WithConsoleWriter(zapcore.AddSync(&buf)), WithLevel(zap.WarnLevel))
+	assert.Same(t, logger.Logger, zap.L())
+
+	L().Info("console only")
+	logger.Warn("both", zap.String("key", "value"))
+	logger.Debug("none")
+
+	assert.Equal(t, 2, strings.Count(buf.String(), "\n"))
+	assert.Contains(t, buf.String(), `"msg":"console only"`)
+	assert.Contains(t, buf.String(), `"key":"value"`)
+	assert.Len(t, exporter.records, 1)
+	assert.Equal(t, "both", *exporter.records[0].Body())
+	assert.Equal(t, "value", attributeMap(exporter.records[0])["key"])
+
+	undo()
+	assert.Same(t, previous, zap.L())
+}
+
+func TestNewDevelopment(t *testing.T) {
+	loggerProvider, exporter := newMemoryProvider()
+	var buf bytes.Buffer
+
+	logger, undo := NewDevelopment(loggerProvider, WithConsoleWriter(zapcore.AddSync(&buf)), WithConsoleLevel(zap.InfoLevel))
+	defer undo()
+
+	logger.Debug("otlp only")
+	logger.Info("both")
+	assert.Panics(t, func() { logger.DPanic("development panics") })
+
+	assert.NotContains(t, buf.String(), "otlp only")
+	assert.Contains(t, buf.String(), "INFO")
+	assert.Len(t, exporter.records, 3)
+}
+
+func TestNewProduction_Sampling(t *testing.T) {
+	tests := []struct {
+		Target  SamplingTarget
+		Console int
+		Records int
+	}{
+		{Target: SampleConsole, Console: 2, Records: 5},
+		{Target: SampleAll, Console: 2, Records: 2},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%+v", test.Target), func(t *testing.T) {
+			loggerProvider, exporter := newMemoryProvider()
+			var buf bytes.Buffer
+			logger, undo := NewProduction(loggerProvider,
+				WithConsoleWriter(zapcore.AddSync(&buf)),
+				WithSampling(&zap.SamplingConfig{Initial: 2}, test.Target),
+			)
+			defer undo()
+
+			for i := 0; i < 5; i++ {
+				logger.Info("repeated")
+			}
+			assert.Equal(t, test.Console, strings.Count(buf.String(), "\n"))
+			assert.Len(t, exporter.records, test.Records)
+		})
+	}
+}
+
+func TestNewProduction_WithoutConsole(t *testing.T) {
+	loggerProvider, exporter := newMemoryProvider()
+	logger, undo := NewProduction(loggerProvider, WithConsoleWriter(nil), WithSampling(nil, SampleAll))
+	defer undo()
+
+	for i := 0; i < 200; i++ {
+		logger.Info("repeated")
+	}
+	assert.Len(t, exporter.records, 200)
+}
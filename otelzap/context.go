@@ -0,0 +1,70 @@
+/*
+Copyright Agoda Services Co.,Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package otelzap
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	traceIDKey = "trace_id"
+	spanIDKey  = "span_id"
+)
+
+// Context returns field carrying ctx, to be used with With or per log call.
+// When several Context fields are present, the last one wins, log call fields override the ones of With.
+// The OpenTelemetry core exports the record with the trace context of the span in ctx.
+// Other cores print only compact trace_id and span_id, or nothing when ctx has no span.
+func Context(ctx context.Context) zap.Field {
+	return zap.Field{Key: contextKey, Type: zapcore.InlineMarshalerType, Interface: contextField{ctx: ctx}}
+}
+
+// contextField is the value of Context fields
+type contextField struct {
+	ctx context.Context
+}
+
+func (f contextField) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	if f.ctx == nil {
+		return nil
+	}
+	if spanCtx := trace.SpanContextFromContext(f.ctx); spanCtx.IsValid() {
+		enc.AddString(traceIDKey, spanCtx.TraceID().String())
+		enc.AddString(spanIDKey, spanCtx.SpanID().String())
+	}
+	return nil
+}
+
+// fieldContext returns context of Context fields, and of span contexts stored with zap.Reflect
+// by previous versions of Ctx
+func fieldContext(f zapcore.Field) (context.Context, bool) {
+	switch f.Type {
+	case zapcore.InlineMarshalerType:
+		if field, ok := f.Interface.(contextField); ok && field.ctx != nil {
+			return field.ctx, true
+		}
+	case zapcore.ReflectType:
+		if spanCtx, ok := f.Interface.(trace.SpanContext); ok && f.Key == contextKey {
+			return trace.ContextWithSpanContext(context.Background(), spanCtx), true
+		}
+	}
+	return nil, false
+}
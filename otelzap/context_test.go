@@ -0,0 +1,100 @@
+package otelzap
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func testSpanContext() context.Context {
+	traceID, _ := trace.TraceIDFromHex("0102030405060708090a0b0c0d0e0f10")
+	spanID, _ := trace.SpanIDFromHex("0102030405060708")
+	return trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	}))
+}
+
+func TestContext(t *testing.T) {
+	loggerProvider, exporter := newMemoryProvider()
+	var buf bytes.Buffer
+	jsonCore := zapcore.NewCore(zapcore.NewJSONEncoder(zapcore.EncoderConfig{MessageKey: "msg"}), zapcore.AddSync(&buf), zap.InfoLevel)
+	logger := zap.New(zapcore.NewTee(jsonCore, NewOtelCore(loggerProvider)))
+	ctx := testSpanContext()
+
+	logger.Info("per call", Context(ctx), zap.String("key", "value"))
+	logger.With(Context(ctx)).Info("with")
+	logger.With(Context(ctx)).Info("overridden", Context(context.Background()))
+	logger.Info("without span", Context(context.Background()))
+
+	assert.Equal(t, `{"msg":"per call","trace_id":"0102030405060708090a0b0c0d0e0f10","span_id":"0102030405060708","key":"value"}
+{"msg":"with","trace_id":"0102030405060708090a0b0c0d0e0f10","span_id":"0102030405060708"}
+{"msg":"overridden","trace_id":"0102030405060708090a0b0c0d0e0f10","span_id":"0102030405060708"}
+{"msg":"without span"}
+`, buf.String())
+
+	assert.Len(t, exporter.records, 4)
+	spanCtx := trace.SpanContextFromContext(ctx)
+	for i, record := range exporter.records[:2] {
+		assert.Equal(t, spanCtx.TraceID(), *record.TraceId(), i)
+		assert.Equal(t, spanCtx.SpanID(), *record.SpanId(), i)
+		assert.Equal(t, spanCtx.TraceFlags(), *record.TraceFlags(), i)
+	}
+	assert.Equal(t, map[string]interface{}{"key": "value"}, attributeMap(exporter.records[0]))
+	assert.Empty(t, attributeMap(exporter.records[1]))
+	// the last context wins, even without span
+	assert.Nil(t, exporter.records[2].TraceId())
+	assert.Nil(t, exporter.records[3].TraceId())
+}
+
+func TestContext_LastWins(t *testing.T) {
+	loggerProvider, exporter := newMemoryProvider()
+	logger := zap.New(NewOtelCore(loggerProvider, WithBaggage()))
+
+	bag, _ := baggage.Parse("user.id=42")
+	spanCtx := baggage.ContextWithBaggage(testSpanContext(), bag)
+	logger.With(Context(spanCtx)).Info("overridden", Context(context.Background()))
+	logger.With(Context(context.Background())).Info("per call", Context(spanCtx))
+
+	assert.Len(t, exporter.records, 2)
+	assert.Nil(t, exporter.records[0].TraceId())
+	assert.Empty(t, attributeMap(exporter.records[0]))
+	assert.True(t, exporter.records[1].TraceId().IsValid())
+	assert.Equal(t, map[string]interface{}{"user.id": "42"}, attributeMap(exporter.records[1]))
+}
+
+func TestContext_LegacyKey(t *testing.T) {
+	loggerProvider, exporter := newMemoryProvider()
+	logger := zap.New(NewOtelCore(loggerProvider))
+	spanCtx := trace.SpanContextFromContext(testSpanContext())
+
+	logger.With(zap.String(contextKey, "checkout")).Info("string")
+	logger.With(zap.Reflect(contextKey, spanCtx)).Info("reflected span context")
+
+	assert.Len(t, exporter.records, 2)
+	assert.Equal(t, map[string]interface{}{"context": "checkout"}, attributeMap(exporter.records[0]))
+	assert.Nil(t, exporter.records[0].TraceId())
+	assert.Empty(t, attributeMap(exporter.records[1]))
+	assert.Equal(t, spanCtx.TraceID(), *exporter.records[1].TraceId())
+}
+
+func TestLogger_Ctx(t *testing.T) {
+	loggerProvider, exporter := newMemoryProvider()
+	logger := &Logger{zap.New(NewOtelCore(loggerProvider))}
+
+	logger.Ctx(testSpanContext()).Info("logger")
+	logger.Sugar().Ctx(testSpanContext()).Infow("sugared", "key", "value")
+
+	assert.Len(t, exporter.records, 2)
+	for _, record := range exporter.records {
+		assert.True(t, record.TraceId().IsValid())
+	}
+	assert.Equal(t, map[string]interface{}{"key": "value"}, attributeMap(exporter.records[1]))
+}
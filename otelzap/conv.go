@@ -25,11 +25,12 @@ import (
 
 	otel "github.com/agoda-com/opentelemetry-logs-go/logs"
 	"go.opentelemetry.io/otel/attribute"
-	semconv "go.opentelemetry.io/otel/semconv/v1.20.0"
 	"go.uber.org/zap/zapcore"
 )
 
-// otelLevel zap level to otlp level converter
+// otelLevel zap level to otlp level converter, DPanic, Panic and Fatal have distinct severities.
+// Custom levels below Debug are TRACE severities and levels above Fatal are FATAL severities,
+// one severity step per level step, e.g. zapcore.DebugLevel-1 is TRACE4.
 func otelLevel(level zapcore.Level) otel.SeverityNumber {
 	switch level {
 	case zapcore.DebugLevel:
@@ -41,13 +42,51 @@ func otelLevel(level zapcore.Level) otel.SeverityNumber {
 	case zapcore.ErrorLevel:
 		return otel.ERROR
 	case zapcore.DPanicLevel:
-		return otel.ERROR
+		return otel.ERROR2
 	case zapcore.PanicLevel:
-		return otel.ERROR
+		return otel.ERROR3
 	case zapcore.FatalLevel:
 		return otel.FATAL
 	}
-	return otel.TRACE
+	if level < zapcore.DebugLevel {
+		return clampSeverity(otel.DEBUG - otel.SeverityNumber(zapcore.DebugLevel-level))
+	}
+	return clampSeverity(otel.FATAL + otel.SeverityNumber(level-zapcore.FatalLevel))
+}
+
+// clampSeverity limits severity number to the valid range of TRACE to FATAL4
+func clampSeverity(severity otel.SeverityNumber) otel.SeverityNumber {
+	if severity < otel.TRACE {
+		return otel.TRACE
+	}
+	if severity > otel.FATAL4 {
+		return otel.FATAL4
+	}
+	return severity
+}
+
+// appendOtelAttribute converts zap Field into OpenTelemetry Attributes with keys nested in namespace and appends them
+// to dst. zap.Namespace fields open a nested namespace for the fields that follow, the updated namespace is returned.
+// zap.Error in a namespace, printed as "<namespace>.error" by zap JSON encoder, is exported like zap.NamedError
+// as "<namespace>.error.message" and so on, only errors outside namespaces use exception semantic conventions.
+func appendOtelAttribute(dst []attribute.KeyValue, namespace string, f zapcore.Field) ([]attribute.KeyValue, string) {
+	if f.Type == zapcore.NamespaceType {
+		if f.Key != "" {
+			namespace += f.Key + "."
+		}
+		return dst, namespace
+	}
+	if err, ok := f.Interface.(error); ok && f.Type == zapcore.ErrorType && namespace != "" {
+		return append(dst, otelError(namespace+f.Key, err)...), namespace
+	}
+
+	attributes := otelAttribute(f)
+	if namespace != "" {
+		for i := range attributes {
+			attributes[i].Key = attribute.Key(namespace) + attributes[i].Key
+		}
+	}
+	return append(dst, attributes...), namespace
 }
 
 // otelAttribute convert zap Field into OpenTelemetry Attribute
@@ -72,20 +111,21 @@ func otelAttribute(f zapcore.Field) []attribute.KeyValue {
 	case zapcore.StringType:
 		return []attribute.KeyValue{attribute.String(f.Key, f.String)}
 	case zapcore.Uint64Type:
-		return []attribute.KeyValue{attribute.Int64(f.Key, int64(uint64(f.Integer)))}
+		return []attribute.KeyValue{{Key: attribute.Key(f.Key), Value: uint64Value(uint64(f.Integer))}}
 	case zapcore.Uint32Type:
 		return []attribute.KeyValue{attribute.Int64(f.Key, int64(uint64(f.Integer)))}
 	case zapcore.Uint16Type:
 		return []attribute.KeyValue{attribute.Int64(f.Key, int64(uint64(f.Integer)))}
 	case zapcore.Uint8Type:
 		return []attribute.KeyValue{attribute.Int64(f.Key, int64(uint64(f.Integer)))}
+	case zapcore.UintptrType:
+		return []attribute.KeyValue{{Key: attribute.Key(f.Key), Value: uint64Value(uint64(f.Integer))}}
 	case zapcore.ErrorType:
-		err := f.Interface.(error)
-		if err != nil {
-			return []attribute.KeyValue{semconv.ExceptionMessage(err.Error())}
+		if err, ok := f.Interface.(error); ok && err != nil {
+			return otelError(f.Key, err)
 		}
 		return []attribute.KeyValue{}
-	case zapcore.SkipType:
+	case zapcore.SkipType, zapcore.NamespaceType:
 		return []attribute.KeyValue{}
 	case zapcore.BinaryType:
 		return []attribute.KeyValue{attribute.String(f.Key, base64.StdEncoding.EncodeToString(f.Interface.([]byte)))}
@@ -108,6 +148,16 @@ func otelAttribute(f zapcore.Field) []attribute.KeyValue {
 			return []attribute.KeyValue{attribute.String(f.Key, "<nil>")}
 		}
 		return []attribute.KeyValue{}
+	case zapcore.ObjectMarshalerType, zapcore.ArrayMarshalerType, zapcore.InlineMarshalerType, zapcore.ReflectType:
+		if v := reflect.ValueOf(f.Interface); f.Interface == nil || v.Kind() == reflect.Ptr && v.IsNil() {
+			if f.Type == zapcore.InlineMarshalerType {
+				return []attribute.KeyValue{}
+			}
+			return []attribute.KeyValue{attribute.String(f.Key, "<nil>")}
+		}
+		enc := newAttributeEncoder("")
+		f.AddTo(enc)
+		return *enc.attributes
 	}
 	// unhandled types will be treated as string
 	return []attribute.KeyValue{attribute.String(f.Key, f.String)}
@@ -4,9 +4,12 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"math"
+	"strings"
 	"testing"
 	"time"
 
+	otel "github.com/agoda-com/opentelemetry-logs-go/logs"
 	"github.com/stretchr/testify/assert"
 	"go.opentelemetry.io/otel/attribute"
 	semconv "go.opentelemetry.io/otel/semconv/v1.20.0"
@@ -19,6 +22,74 @@ var (
 	testNow      = time.Now()
 )
 
+type testUser struct {
+	Name    string
+	Age     int
+	Address *testAddress
+}
+
+func (u testUser) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("name", u.Name)
+	enc.AddInt("age", u.Age)
+	if u.Address != nil {
+		return enc.AddObject("address", u.Address)
+	}
+	return nil
+}
+
+type testAddress struct {
+	City string `json:"city"`
+}
+
+func (a *testAddress) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("city", a.City)
+	return nil
+}
+
+type testFailingObject struct{}
+
+func (testFailingObject) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddBool("partial", true)
+	return errors.New("failed")
+}
+
+// testRecursiveObject marshals itself forever
+type testRecursiveObject struct{}
+
+func (o testRecursiveObject) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	return enc.AddObject("next", o)
+}
+
+// testStackError is formatted like errors of github.com/pkg/errors
+type testStackError struct{}
+
+func (testStackError) Error() string {
+	return "stack error"
+}
+
+func (e testStackError) Format(s fmt.State, verb rune) {
+	if s.Flag('+') {
+		_, _ = fmt.Fprint(s, "stack error\nmain.main\n\tmain.go:10")
+		return
+	}
+	_, _ = fmt.Fprint(s, e.Error())
+}
+
+type testPtrError struct{}
+
+func (*testPtrError) Error() string {
+	return "ptr error"
+}
+
+type testLongArray struct{}
+
+func (testLongArray) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for i := 0; i < maxArrayLength+10; i++ {
+		enc.AppendInt(i)
+	}
+	return nil
+}
+
 func TestOTeLAttributeMapping(t *testing.T) {
 	tests := []struct {
 		Input    zapcore.Field
@@ -27,14 +98,74 @@ func TestOTeLAttributeMapping(t *testing.T) {
 		{Input: zap.Bool(testFieldKey, true), Expected: []attribute.KeyValue{attribute.Bool(testFieldKey, true)}},
 		{Input: zap.Float64(testFieldKey, 123.123), Expected: []attribute.KeyValue{attribute.Float64(testFieldKey, 123.123)}},
 		{Input: zap.Int(testFieldKey, 123), Expected: []attribute.KeyValue{attribute.Int64(testFieldKey, 123)}},
+		{Input: zap.Uint64(testFieldKey, 123), Expected: []attribute.KeyValue{attribute.Int64(testFieldKey, 123)}},
+		{Input: zap.Uint64(testFieldKey, math.MaxUint64), Expected: []attribute.KeyValue{attribute.String(testFieldKey, "18446744073709551615")}},
+		{Input: zap.Uintptr(testFieldKey, 0xc000), Expected: []attribute.KeyValue{attribute.Int64(testFieldKey, 0xc000)}},
 		{Input: zap.String(testFieldKey, "hello"), Expected: []attribute.KeyValue{attribute.String(testFieldKey, "hello")}},
 		{Input: zap.ByteString(testFieldKey, []byte("hello")), Expected: []attribute.KeyValue{attribute.String(testFieldKey, "hello")}},
 		{Input: zap.Binary(testFieldKey, []byte{1, 0, 0, 1}), Expected: []attribute.KeyValue{attribute.String(testFieldKey, "AQAAAQ==")}},
 		{Input: zap.Duration(testFieldKey, time.Minute), Expected: []attribute.KeyValue{attribute.Float64(testFieldKey, time.Minute.Seconds())}},
 		{Input: zap.Time(testFieldKey, testNow), Expected: []attribute.KeyValue{attribute.Int64(testFieldKey, testNow.Unix())}},
 		{Input: zap.Stringer(testFieldKey, bytes.NewBuffer([]byte("hello"))), Expected: []attribute.KeyValue{attribute.String(testFieldKey, "hello")}},
-		{Input: zap.Error(errors.New("world")), Expected: []attribute.KeyValue{semconv.ExceptionMessage("world")}},
+		{Input: zap.Error(errors.New("world")), Expected: []attribute.KeyValue{semconv.ExceptionMessage("world"), semconv.ExceptionType("*errors.errorString")}},
+		{Input: zap.NamedError("db", errors.New("timeout")), Expected: []attribute.KeyValue{attribute.String("db.message", "timeout"), attribute.String("db.type", "*errors.errorString")}},
+		{Input: zap.Error(testStackError{}), Expected: []attribute.KeyValue{
+			semconv.ExceptionMessage("stack error"),
+			semconv.ExceptionType("otelzap.testStackError"),
+			semconv.ExceptionStacktrace("stack error\nmain.main\n\tmain.go:10"),
+		}},
+		{Input: zap.Error((*testPtrError)(nil)), Expected: []attribute.KeyValue{semconv.ExceptionMessage("<nil>"), semconv.ExceptionType("*otelzap.testPtrError")}},
+		{Input: zap.Error(nil), Expected: []attribute.KeyValue{}},
 		{Input: zap.Skip(), Expected: []attribute.KeyValue{}},
+		{Input: zap.Object("user", testUser{Name: "alice", Age: 30, Address: &testAddress{City: "Bangkok"}}), Expected: []attribute.KeyValue{
+			attribute.String("user.name", "alice"),
+			attribute.Int64("user.age", 30),
+			attribute.String("user.address.city", "Bangkok"),
+		}},
+		{Input: zap.Object("user", (*testAddress)(nil)), Expected: []attribute.KeyValue{attribute.String("user", "<nil>")}},
+		{Input: zap.Object("failing", testFailingObject{}), Expected: []attribute.KeyValue{
+			attribute.Bool("failing.partial", true),
+			attribute.String("failingError", "failed"),
+		}},
+		{Input: zap.Object("o", testRecursiveObject{}), Expected: []attribute.KeyValue{
+			attribute.String("o"+strings.Repeat(".next", maxEncoderDepth), "!MAXDEPTH"),
+		}},
+		{Input: zap.Inline(testUser{Name: "bob", Age: 40}), Expected: []attribute.KeyValue{
+			attribute.String("name", "bob"),
+			attribute.Int64("age", 40),
+		}},
+		{Input: zap.Ints("ints", []int{1, 2, 3}), Expected: []attribute.KeyValue{attribute.Int64Slice("ints", []int64{1, 2, 3})}},
+		{Input: zap.Strings("strings", []string{"a", "b"}), Expected: []attribute.KeyValue{attribute.StringSlice("strings", []string{"a", "b"})}},
+		{Input: zap.Bools("bools", []bool{true, false}), Expected: []attribute.KeyValue{attribute.BoolSlice("bools", []bool{true, false})}},
+		{Input: zap.Float64s("floats", []float64{1.5, 2}), Expected: []attribute.KeyValue{attribute.Float64Slice("floats", []float64{1.5, 2})}},
+		{Input: zap.Durations("durations", []time.Duration{time.Second}), Expected: []attribute.KeyValue{attribute.Float64Slice("durations", []float64{1})}},
+		{Input: zap.Uint64s("uints", []uint64{1, math.MaxUint64}), Expected: []attribute.KeyValue{attribute.StringSlice("uints", []string{"1", "18446744073709551615"})}},
+		{Input: zap.Object("o", zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+			enc.AddUint64("max", math.MaxUint64)
+			enc.AddUint("small", 1)
+			return nil
+		})), Expected: []attribute.KeyValue{attribute.String("o.max", "18446744073709551615"), attribute.Int64("o.small", 1)}},
+		{Input: zap.Reflect("big", uint64(math.MaxUint64)), Expected: []attribute.KeyValue{attribute.String("big", "18446744073709551615")}},
+		{Input: zap.Strings("empty", []string{}), Expected: []attribute.KeyValue{attribute.StringSlice("empty", []string{})}},
+		{Input: zap.Objects("users", []testUser{{Name: "alice", Age: 30}, {Name: "bob", Age: 40}}), Expected: []attribute.KeyValue{
+			attribute.String("users.0.name", "alice"),
+			attribute.Int64("users.0.age", 30),
+			attribute.String("users.1.name", "bob"),
+			attribute.Int64("users.1.age", 40),
+		}},
+		{Input: zap.Array("long", testLongArray{}), Expected: []attribute.KeyValue{attribute.Int64Slice("long", testRange(maxArrayLength))}},
+		{Input: zap.Any("cfg", map[string]interface{}{"debug": true, "port": 8080, "ratio": 0.5, "hosts": []string{"a", "b"}, "db": map[string]string{"name": "main"}}), Expected: []attribute.KeyValue{
+			attribute.Bool("cfg.debug", true),
+			attribute.Int64("cfg.port", 8080),
+			attribute.Float64("cfg.ratio", 0.5),
+			attribute.StringSlice("cfg.hosts", []string{"a", "b"}),
+			attribute.String("cfg.db.name", "main"),
+		}},
+		{Input: zap.Reflect("address", testAddress{City: "Bangkok"}), Expected: []attribute.KeyValue{attribute.String("address.city", "Bangkok")}},
+		{Input: zap.Reflect("mixed", []interface{}{1, "a", true}), Expected: []attribute.KeyValue{attribute.StringSlice("mixed", []string{"1", "a", "true"})}},
+		{Input: zap.Reflect("number", 42), Expected: []attribute.KeyValue{attribute.Int64("number", 42)}},
+		{Input: zap.Reflect("nil", nil), Expected: []attribute.KeyValue{attribute.String("nil", "<nil>")}},
+		{Input: zap.Reflect("channel", make(chan int)), Expected: []attribute.KeyValue{attribute.String("channelError", "json: unsupported type: chan int")}},
 	}
 
 	for _, test := range tests {
@@ -44,3 +175,71 @@ func TestOTeLAttributeMapping(t *testing.T) {
 		})
 	}
 }
+
+func TestOTeLAttributeMapping_MaxAttributes(t *testing.T) {
+	values := map[string]int{}
+	for i := 0; i < maxEncoderAttributes+10; i++ {
+		values[fmt.Sprintf("key%d", i)] = i
+	}
+	assert.Len(t, otelAttribute(zap.Any("values", values)), maxEncoderAttributes)
+}
+
+// testNested returns map nested depth times, e.g. {"next": {"next": {"value": 1}}}
+func testNested(depth int) interface{} {
+	var value interface{} = map[string]interface{}{"value": uint64(math.MaxUint64), "list": []interface{}{1, map[string]int{"n": 2}}}
+	for i := 0; i < depth; i++ {
+		value = map[string]interface{}{"next": value}
+	}
+	return value
+}
+
+func TestOTeLAttributeMapping_DeepReflected(t *testing.T) {
+	prefix := "deep" + strings.Repeat(".next", 10)
+	assert.ElementsMatch(t, []attribute.KeyValue{
+		attribute.String(prefix+".value", "18446744073709551615"),
+		attribute.Int64Slice(prefix+".list", []int64{1}),
+		attribute.Int64(prefix+".list.1.n", 2),
+	}, otelAttribute(zap.Any("deep", testNested(10))))
+}
+
+func BenchmarkOTeLAttributeMapping_DeepReflected(b *testing.B) {
+	field := zap.Reflect("deep", testNested(maxEncoderDepth-2))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		otelAttribute(field)
+	}
+}
+
+func testRange(n int) []int64 {
+	result := make([]int64, n)
+	for i := range result {
+		result[i] = int64(i)
+	}
+	return result
+}
+
+func TestOTeLLevelMapping(t *testing.T) {
+	tests := []struct {
+		Input    zapcore.Level
+		Expected otel.SeverityNumber
+	}{
+		{Input: zapcore.DebugLevel, Expected: otel.DEBUG},
+		{Input: zapcore.InfoLevel, Expected: otel.INFO},
+		{Input: zapcore.WarnLevel, Expected: otel.WARN},
+		{Input: zapcore.ErrorLevel, Expected: otel.ERROR},
+		{Input: zapcore.DPanicLevel, Expected: otel.ERROR2},
+		{Input: zapcore.PanicLevel, Expected: otel.ERROR3},
+		{Input: zapcore.FatalLevel, Expected: otel.FATAL},
+		{Input: zapcore.DebugLevel - 1, Expected: otel.TRACE4},
+		{Input: zapcore.DebugLevel - 4, Expected: otel.TRACE},
+		{Input: zapcore.DebugLevel - 100, Expected: otel.TRACE},
+		{Input: zapcore.FatalLevel + 1, Expected: otel.FATAL2},
+		{Input: zapcore.FatalLevel + 100, Expected: otel.FATAL4},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%+v", test.Input), func(t *testing.T) {
+			assert.Equal(t, test.Expected, otelLevel(test.Input))
+		})
+	}
+}
@@ -17,6 +17,10 @@ limitations under the License.
 package otelzap
 
 import (
+	"context"
+	"sync"
+	"time"
+
 	otel "github.com/agoda-com/opentelemetry-logs-go/logs"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/sdk/instrumentation"
@@ -31,10 +35,32 @@ const (
 
 // This class provide interface for OTLP logger
 type otlpCore struct {
-	logger otel.Logger
+	logger         otel.Logger
+	loggerProvider otel.LoggerProvider
+
+	fields         []zapcore.Field
+	levelEnabler   zapcore.LevelEnabler
+	addCaller      bool
+	severityMapper SeverityMapper
+
+	flusher      Flusher
+	flushTimeout time.Duration
+
+	// loggerNameKey is the attribute key of the logger name, empty when not exported as attribute
+	loggerNameKey     string
+	addBaggage        bool
+	baggageAllow      []string
+	baggageDeny       []string
+	contextExtractors []func(context.Context) []attribute.KeyValue
+
+	// scopedLoggers caches loggers per logger name when it's exported as instrumentation scope, nil otherwise
+	scopedLoggers *sync.Map // map[string]*scopedLogger
+}
 
-	fields       []zapcore.Field
-	levelEnabler zapcore.LevelEnabler
+// scopedLogger is a logger of the instrumentation scope named after zap logger
+type scopedLogger struct {
+	logger otel.Logger
+	scope  instrumentation.Scope
 }
 
 var instrumentationScope = instrumentation.Scope{
@@ -43,19 +69,39 @@ var instrumentationScope = instrumentation.Scope{
 	SchemaURL: semconv.SchemaURL,
 }
 
+// scoped returns logger and instrumentation scope for the zap logger name
+func (c *otlpCore) scoped(name string) (otel.Logger, *instrumentation.Scope) {
+	if c.scopedLoggers == nil || name == "" {
+		return c.logger, &instrumentationScope
+	}
+	if cached, ok := c.scopedLoggers.Load(name); ok {
+		scoped := cached.(*scopedLogger)
+		return scoped.logger, &scoped.scope
+	}
+
+	scope := instrumentationScope
+	scope.Name = name
+	cached, _ := c.scopedLoggers.LoadOrStore(name, &scopedLogger{
+		logger: c.loggerProvider.Logger(
+			scope.Name,
+			otel.WithInstrumentationVersion(scope.Version),
+			otel.WithSchemaURL(scope.SchemaURL),
+		),
+		scope: scope,
+	})
+	scoped := cached.(*scopedLogger)
+	return scoped.logger, &scoped.scope
+}
+
 func (c *otlpCore) Enabled(level zapcore.Level) bool {
 	return c.levelEnabler.Enabled(level)
 }
 
 func (c *otlpCore) With(f []zapcore.Field) zapcore.Core {
-	fields := c.fields
-	fields = append(fields, f...)
-
-	return &otlpCore{
-		logger:       c.logger,
-		fields:       fields,
-		levelEnabler: c.levelEnabler,
-	}
+	clone := *c
+	// full slice expression forces a copy, so sibling cores don't overwrite each other's fields
+	clone.fields = append(c.fields[:len(c.fields):len(c.fields)], f...)
+	return &clone
 }
 
 // Check OTLP zap extension method to check if logger is enabled
@@ -66,43 +112,70 @@ func (c *otlpCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *za
 	return checked
 }
 
+// Sync flushes records buffered by the logger provider, it is a no-op without Flusher
 func (c *otlpCore) Sync() error {
-	return nil
+	if c.flusher == nil {
+		return nil
+	}
+	if c.flushTimeout <= 0 {
+		return c.flusher.ForceFlush(context.Background())
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), c.flushTimeout)
+	defer cancel()
+	return c.flusher.ForceFlush(ctx)
 }
 
 func (c *otlpCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
 	var attributes []attribute.KeyValue
 	var spanCtx *trace.SpanContext
 
+	// namespace is the key prefix of fields following zap.Namespace, it stays open from common to log fields
+	var namespace string
+
+	// ctx is the context of the last Context field, log fields override common ones.
+	// Trace context, baggage and extractors all use this one context.
+	var ctx context.Context
+
 	// add common zap log fields as attributes
 	for _, s := range c.fields {
-		if s.Key == "context" {
-			if ctxValue, ok := s.Interface.(trace.SpanContext); ok {
-				spanCtx = &ctxValue
-			}
+		if fieldCtx, ok := fieldContext(s); ok {
+			ctx = fieldCtx
 		} else {
-			attributes = append(attributes, otelAttribute(s)...)
+			attributes, namespace = appendOtelAttribute(attributes, namespace, s)
 		}
 	}
 	// add zap log fields as attributes
 	for _, s := range fields {
-		attributes = append(attributes, otelAttribute(s)...)
+		if fieldCtx, ok := fieldContext(s); ok {
+			ctx = fieldCtx
+		} else {
+			attributes, namespace = appendOtelAttribute(attributes, namespace, s)
+		}
 	}
-
-	if ent.Level > zapcore.InfoLevel {
-		callerString := ent.Caller.String()
-
-		if len(callerString) > 0 {
-			attributes = append(attributes, semconv.ExceptionType(callerString))
+	if ctx != nil {
+		if ctxValue := trace.SpanContextFromContext(ctx); ctxValue.IsValid() {
+			spanCtx = &ctxValue
 		}
+	}
 
-		if len(ent.Stack) > 0 {
-			attributes = append(attributes, semconv.ExceptionStacktrace(ent.Stack))
-		}
+	// attributes of the context go first, the same as in otelslog
+	if ctx != nil && (c.addBaggage || len(c.contextExtractors) > 0) {
+		attributes = append(c.contextAttributes(ctx), attributes...)
 	}
 
-	severityString := ent.Level.String()
-	severity := otelLevel(ent.Level)
+	if c.loggerNameKey != "" && ent.LoggerName != "" {
+		attributes = append(attributes, attribute.String(c.loggerNameKey, ent.LoggerName))
+	}
+	if c.addCaller {
+		attributes = append(attributes, callerAttributes(ent.Caller)...)
+	}
+	if len(ent.Stack) > 0 {
+		attributes = append(attributes, codeStacktraceKey.String(ent.Stack))
+	}
+
+	logger, scope := c.scoped(ent.LoggerName)
+	severity, severityString := c.severityMapper(ent.Level)
+	severity = clampSeverity(severity)
 
 	var traceID *trace.TraceID = nil
 	var spanID *trace.SpanID = nil
@@ -126,11 +199,16 @@ func (c *otlpCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
 		SeverityNumber:       &severity,
 		Body:                 &ent.Message,
 		Resource:             nil,
-		InstrumentationScope: &instrumentationScope,
+		InstrumentationScope: scope,
 		Attributes:           &attributes,
 	}
 
 	r := otel.NewLogRecord(lrc)
-	c.logger.Emit(r)
+	logger.Emit(r)
+
+	// the process is about to exit or panic, don't lose the record in the batch, same as zapcore.ioCore
+	if ent.Level > zapcore.ErrorLevel {
+		return c.Sync()
+	}
 	return nil
 }
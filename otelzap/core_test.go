@@ -1,10 +1,20 @@
 package otelzap
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	otel "github.com/agoda-com/opentelemetry-logs-go/logs"
 	sdk "github.com/agoda-com/opentelemetry-logs-go/sdk/logs"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/zap"
-	"testing"
+	"go.uber.org/zap/zapcore"
 )
 
 func TestLevelEnabler_ChangeLevelAfterCreation(t *testing.T) {
@@ -18,3 +28,280 @@ func TestLevelEnabler_ChangeLevelAfterCreation(t *testing.T) {
 
 	assert.True(t, core.Enabled(zap.InfoLevel))
 }
+
+type memoryExporter struct {
+	mu      sync.Mutex
+	records []sdk.ReadableLogRecord
+}
+
+func (e *memoryExporter) Export(ctx context.Context, batch []sdk.ReadableLogRecord) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.records = append(e.records, batch...)
+	return nil
+}
+
+func (e *memoryExporter) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+func newMemoryProvider() (*sdk.LoggerProvider, *memoryExporter) {
+	exporter := &memoryExporter{}
+	return sdk.NewLoggerProvider(sdk.WithSyncer(exporter)), exporter
+}
+
+// attributeMap converts record attributes into map for assertions
+func attributeMap(record sdk.ReadableLogRecord) map[string]interface{} {
+	result := map[string]interface{}{}
+	if record.Attributes() != nil {
+		for _, kv := range *record.Attributes() {
+			result[string(kv.Key)] = kv.Value.AsInterface()
+		}
+	}
+	return result
+}
+
+// flattenJSON converts JSON object into dotted keys, the same way objects are flattened into attributes
+func flattenJSON(prefix string, object map[string]interface{}, dst map[string]interface{}) {
+	for key, value := range object {
+		if nested, ok := value.(map[string]interface{}); ok {
+			flattenJSON(prefix+key+".", nested, dst)
+		} else {
+			dst[prefix+key] = value
+		}
+	}
+}
+
+func TestOtelCore_Namespace(t *testing.T) {
+	loggerProvider, exporter := newMemoryProvider()
+	var buf bytes.Buffer
+	jsonCore := zapcore.NewCore(zapcore.NewJSONEncoder(zapcore.EncoderConfig{}), zapcore.AddSync(&buf), zap.InfoLevel)
+	logger := zap.New(zapcore.NewTee(jsonCore, NewOtelCore(loggerProvider)))
+
+	logger.
+		With(zap.String("service", "api"), zap.Namespace("http"), zap.String("method", "GET")).
+		Info("request",
+			zap.Int("status", 200),
+			zap.Dict("client", zap.String("ip", "10.0.0.1"), zap.Namespace("geo"), zap.String("country", "TH")),
+			zap.Namespace("timing"),
+			zap.Float64("total", 1.5),
+		)
+
+	var line map[string]interface{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &line))
+	expected := map[string]interface{}{}
+	flattenJSON("", line, expected)
+
+	assert.Len(t, exporter.records, 1)
+	actual := attributeMap(exporter.records[0])
+	for key, value := range actual {
+		if number, ok := value.(int64); ok {
+			actual[key] = float64(number)
+		}
+	}
+	assert.Equal(t, expected, actual)
+	assert.Equal(t, map[string]interface{}{
+		"service":                 "api",
+		"http.method":             "GET",
+		"http.status":             float64(200),
+		"http.client.ip":          "10.0.0.1",
+		"http.client.geo.country": "TH",
+		"http.timing.total":       1.5,
+	}, actual)
+}
+
+func TestOtelCore_NamespaceError(t *testing.T) {
+	loggerProvider, exporter := newMemoryProvider()
+	var buf bytes.Buffer
+	jsonCore := zapcore.NewCore(zapcore.NewJSONEncoder(zapcore.EncoderConfig{}), zapcore.AddSync(&buf), zap.InfoLevel)
+	logger := zap.New(zapcore.NewTee(jsonCore, NewOtelCore(loggerProvider)))
+
+	logger.Error("failed", zap.Error(errors.New("outer")), zap.Namespace("db"), zap.Error(errors.New("timeout")))
+
+	var line map[string]interface{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &line))
+	assert.Equal(t, "timeout", line["db"].(map[string]interface{})["error"])
+
+	assert.Len(t, exporter.records, 1)
+	assert.Equal(t, map[string]interface{}{
+		"exception.message": "outer",
+		"exception.type":    "*errors.errorString",
+		"db.error.message":  "timeout",
+		"db.error.type":     "*errors.errorString",
+	}, attributeMap(exporter.records[0]))
+}
+
+func TestOtelCore_Caller(t *testing.T) {
+	loggerProvider, exporter := newMemoryProvider()
+	logger := zap.New(NewOtelCore(loggerProvider), zap.AddCaller(), zap.AddStacktrace(zap.ErrorLevel))
+	withoutCaller := zap.New(NewOtelCore(loggerProvider, WithCaller(false)), zap.AddCaller())
+
+	_, file, line, _ := runtime.Caller(0)
+	logger.Warn("warning")
+	logger.Error("failed", zap.Error(errors.New("boom")))
+	withoutCaller.Info("no caller")
+
+	assert.Len(t, exporter.records, 3)
+	warning := attributeMap(exporter.records[0])
+	assert.Equal(t, map[string]interface{}{
+		"code.filepath":  file,
+		"code.lineno":    int64(line + 1),
+		"code.function":  "TestOtelCore_Caller",
+		"code.namespace": "github.com/agoda-com/opentelemetry-go/otelzap",
+	}, warning)
+
+	failed := attributeMap(exporter.records[1])
+	assert.Equal(t, "boom", failed["exception.message"])
+	assert.Equal(t, "*errors.errorString", failed["exception.type"])
+	assert.Contains(t, failed["code.stacktrace"], "TestOtelCore_Caller")
+	assert.Equal(t, int64(line+2), failed["code.lineno"])
+
+	assert.Empty(t, attributeMap(exporter.records[2]))
+}
+
+// logGeneric logs from a generic function, its runtime name is "otelzap.logGeneric[...]"
+func logGeneric[T any](logger *zap.Logger, value T) {
+	logger.Info("generic", zap.Any("value", value))
+}
+
+func TestOtelCore_CallerGeneric(t *testing.T) {
+	loggerProvider, exporter := newMemoryProvider()
+	logger := zap.New(NewOtelCore(loggerProvider), zap.AddCaller())
+
+	logGeneric(logger, 1)
+
+	assert.Len(t, exporter.records, 1)
+	attributes := attributeMap(exporter.records[0])
+	assert.Equal(t, "logGeneric", attributes["code.function"])
+	assert.Equal(t, "github.com/agoda-com/opentelemetry-go/otelzap", attributes["code.namespace"])
+}
+
+func TestSplitFunctionName(t *testing.T) {
+	tests := []struct {
+		Input     string
+		Namespace string
+		Function  string
+	}{
+		{Input: "main.main", Namespace: "main", Function: "main"},
+		{Input: "github.com/org/repo/pkg.(*Type).Method", Namespace: "github.com/org/repo/pkg.(*Type)", Function: "Method"},
+		{Input: "github.com/org/repo.v2/pkg.Func.func1", Namespace: "github.com/org/repo.v2/pkg.Func", Function: "func1"},
+		{Input: "pkg.F[...]", Namespace: "pkg", Function: "F"},
+		{Input: "pkg.F[...].func1", Namespace: "pkg.F", Function: "func1"},
+		{Input: "github.com/org/repo/pkg.(*Type[...]).Method", Namespace: "github.com/org/repo/pkg.(*Type)", Function: "Method"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Input, func(t *testing.T) {
+			namespace, function := splitFunctionName(test.Input)
+			assert.Equal(t, test.Namespace, namespace)
+			assert.Equal(t, test.Function, function)
+		})
+	}
+}
+
+type countingFlusher struct {
+	calls    int
+	deadline bool
+}
+
+func (f *countingFlusher) ForceFlush(ctx context.Context) error {
+	f.calls++
+	_, f.deadline = ctx.Deadline()
+	return nil
+}
+
+func TestOtelCore_Sync(t *testing.T) {
+	exporter := &memoryExporter{}
+	loggerProvider := sdk.NewLoggerProvider(sdk.WithBatcher(exporter, sdk.WithBatchTimeout(time.Hour)))
+	logger := zap.New(NewOtelCore(loggerProvider))
+
+	logger.Info("buffered")
+	assert.Empty(t, exporter.records)
+	assert.NoError(t, logger.Sync())
+	assert.Len(t, exporter.records, 1)
+
+	assert.Panics(t, func() { logger.Panic("panic") })
+	assert.Len(t, exporter.records, 2)
+}
+
+func TestOtelCore_WithFlushTimeoutZero(t *testing.T) {
+	exporter := &memoryExporter{}
+	loggerProvider := sdk.NewLoggerProvider(sdk.WithBatcher(exporter, sdk.WithBatchTimeout(time.Hour)))
+	flusher := &countingFlusher{}
+	logger := zap.New(NewOtelCore(loggerProvider, WithFlushTimeout(0)))
+	noDeadline := zap.New(NewOtelCore(loggerProvider, WithFlusher(flusher), WithFlushTimeout(-time.Second)))
+
+	logger.Info("buffered")
+	assert.NoError(t, logger.Sync())
+	assert.Len(t, exporter.records, 1)
+
+	assert.NoError(t, noDeadline.Sync())
+	assert.Equal(t, 1, flusher.calls)
+	assert.False(t, flusher.deadline)
+}
+
+func TestOtelCore_WithFlusher(t *testing.T) {
+	flusher := &countingFlusher{}
+	logger := zap.New(NewOtelCore(sdk.NewLoggerProvider(), WithFlusher(flusher), WithFlushTimeout(time.Second)))
+
+	logger.Info("info")
+	logger.Error("error")
+	assert.Equal(t, 0, flusher.calls)
+
+	assert.NoError(t, logger.Sync())
+	assert.Equal(t, 1, flusher.calls)
+	assert.True(t, flusher.deadline)
+
+	// DPanic panics only in development, but is flushed anyway
+	logger.DPanic("dpanic")
+	assert.Equal(t, 2, flusher.calls)
+}
+
+func TestOtelCore_LoggerName(t *testing.T) {
+	loggerProvider, exporter := newMemoryProvider()
+	attributeLogger := zap.New(NewOtelCore(loggerProvider, WithLoggerNameKey("logger.name")))
+	scopeLogger := zap.New(NewOtelCore(loggerProvider, WithLoggerNameScope()))
+
+	attributeLogger.Named("kafka").Named("consumer").Info("attribute")
+	scopeLogger.Named("kafka.consumer").Info("scope")
+	scopeLogger.Named("kafka.consumer").With(zap.String("topic", "orders")).Info("cached scope")
+	scopeLogger.Info("unnamed")
+
+	assert.Len(t, exporter.records, 4)
+	assert.Equal(t, map[string]interface{}{"logger.name": "kafka.consumer"}, attributeMap(exporter.records[0]))
+	assert.Equal(t, instrumentationName, exporter.records[0].InstrumentationScope().Name)
+
+	assert.Empty(t, attributeMap(exporter.records[1]))
+	assert.Equal(t, "kafka.consumer", exporter.records[1].InstrumentationScope().Name)
+	assert.Equal(t, Version(), exporter.records[1].InstrumentationScope().Version)
+	assert.Same(t, exporter.records[1].InstrumentationScope(), exporter.records[2].InstrumentationScope())
+	assert.Equal(t, instrumentationName, exporter.records[3].InstrumentationScope().Name)
+}
+
+func TestOtelCore_SeverityMapper(t *testing.T) {
+	const traceLevel = zapcore.DebugLevel - 1
+	loggerProvider, exporter := newMemoryProvider()
+	logger := zap.New(NewOtelCore(loggerProvider,
+		WithLevel(traceLevel),
+		WithSeverityMapper(func(level zapcore.Level) (otel.SeverityNumber, string) {
+			if level == traceLevel {
+				return otel.TRACE, "trace"
+			}
+			if level == zapcore.InfoLevel {
+				return 100, "out of range"
+			}
+			return DefaultSeverityMapper(level)
+		}),
+	))
+
+	logger.Log(traceLevel, "trace")
+	logger.Info("clamped")
+	logger.DPanic("dpanic")
+
+	assert.Len(t, exporter.records, 3)
+	assert.Equal(t, otel.TRACE, *exporter.records[0].SeverityNumber())
+	assert.Equal(t, "trace", *exporter.records[0].SeverityText())
+	assert.Equal(t, otel.FATAL4, *exporter.records[1].SeverityNumber())
+	assert.Equal(t, otel.ERROR2, *exporter.records[2].SeverityNumber())
+	assert.Equal(t, "dpanic", *exporter.records[2].SeverityText())
+}
@@ -0,0 +1,477 @@
+/*
+Copyright Agoda Services Co.,Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package otelzap
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	// maxEncoderDepth bounds nesting of objects and arrays, deeper values are exported as "!MAXDEPTH"
+	maxEncoderDepth = 16
+	// maxEncoderAttributes bounds number of attributes a single field is flattened into, the rest is dropped
+	maxEncoderAttributes = 256
+	// maxArrayLength bounds number of elements of exported slices, the rest is dropped
+	maxArrayLength = 256
+)
+
+// attributeEncoder is zapcore.ObjectEncoder collecting OpenTelemetry attributes.
+// Nested objects are flattened into dotted keys, e.g. "user.address.city", arrays of primitives become typed slices.
+type attributeEncoder struct {
+	// attributes is shared with nested encoders, so the size limit applies to the whole field
+	attributes *[]attribute.KeyValue
+	prefix     string
+	depth      int
+}
+
+var _ zapcore.ObjectEncoder = &attributeEncoder{}
+
+func newAttributeEncoder(prefix string) *attributeEncoder {
+	return &attributeEncoder{attributes: &[]attribute.KeyValue{}, prefix: prefix}
+}
+
+func (e *attributeEncoder) add(key string, value attribute.Value) {
+	if len(*e.attributes) >= maxEncoderAttributes {
+		return
+	}
+	*e.attributes = append(*e.attributes, attribute.KeyValue{Key: attribute.Key(e.prefix + key), Value: value})
+}
+
+func (e *attributeEncoder) nested(prefix string) *attributeEncoder {
+	return &attributeEncoder{attributes: e.attributes, prefix: prefix, depth: e.depth + 1}
+}
+
+func (e *attributeEncoder) AddArray(key string, marshaler zapcore.ArrayMarshaler) error {
+	if e.depth >= maxEncoderDepth {
+		e.add(key, attribute.StringValue("!MAXDEPTH"))
+		return nil
+	}
+	arr := &arrayEncoder{object: e.nested(""), key: e.prefix + key}
+	err := marshaler.MarshalLogArray(arr)
+	arr.flush()
+	return err
+}
+
+func (e *attributeEncoder) AddObject(key string, marshaler zapcore.ObjectMarshaler) error {
+	if e.depth >= maxEncoderDepth {
+		e.add(key, attribute.StringValue("!MAXDEPTH"))
+		return nil
+	}
+	return marshaler.MarshalLogObject(e.nested(e.prefix + key + "."))
+}
+
+func (e *attributeEncoder) AddBinary(key string, value []byte) {
+	e.add(key, attribute.StringValue(base64.StdEncoding.EncodeToString(value)))
+}
+
+func (e *attributeEncoder) AddByteString(key string, value []byte) {
+	e.add(key, attribute.StringValue(string(value)))
+}
+
+func (e *attributeEncoder) AddBool(key string, value bool) {
+	e.add(key, attribute.BoolValue(value))
+}
+
+func (e *attributeEncoder) AddComplex128(key string, value complex128) {
+	e.add(key, attribute.StringValue(strconv.FormatComplex(value, 'g', -1, 128)))
+}
+
+func (e *attributeEncoder) AddComplex64(key string, value complex64) {
+	e.add(key, attribute.StringValue(strconv.FormatComplex(complex128(value), 'g', -1, 64)))
+}
+
+func (e *attributeEncoder) AddDuration(key string, value time.Duration) {
+	e.add(key, attribute.Float64Value(value.Seconds()))
+}
+
+func (e *attributeEncoder) AddFloat64(key string, value float64) {
+	e.add(key, attribute.Float64Value(value))
+}
+
+func (e *attributeEncoder) AddFloat32(key string, value float32) {
+	e.add(key, attribute.Float64Value(float64(value)))
+}
+
+func (e *attributeEncoder) AddInt(key string, value int) {
+	e.add(key, attribute.Int64Value(int64(value)))
+}
+
+func (e *attributeEncoder) AddInt64(key string, value int64) {
+	e.add(key, attribute.Int64Value(value))
+}
+
+func (e *attributeEncoder) AddInt32(key string, value int32) {
+	e.add(key, attribute.Int64Value(int64(value)))
+}
+
+func (e *attributeEncoder) AddInt16(key string, value int16) {
+	e.add(key, attribute.Int64Value(int64(value)))
+}
+
+func (e *attributeEncoder) AddInt8(key string, value int8) {
+	e.add(key, attribute.Int64Value(int64(value)))
+}
+
+func (e *attributeEncoder) AddString(key, value string) {
+	e.add(key, attribute.StringValue(value))
+}
+
+func (e *attributeEncoder) AddTime(key string, value time.Time) {
+	e.add(key, attribute.Int64Value(value.Unix()))
+}
+
+func (e *attributeEncoder) AddUint(key string, value uint) {
+	e.add(key, uint64Value(uint64(value)))
+}
+
+func (e *attributeEncoder) AddUint64(key string, value uint64) {
+	e.add(key, uint64Value(uint64(value)))
+}
+
+func (e *attributeEncoder) AddUint32(key string, value uint32) {
+	e.add(key, attribute.Int64Value(int64(value)))
+}
+
+func (e *attributeEncoder) AddUint16(key string, value uint16) {
+	e.add(key, attribute.Int64Value(int64(value)))
+}
+
+func (e *attributeEncoder) AddUint8(key string, value uint8) {
+	e.add(key, attribute.Int64Value(int64(value)))
+}
+
+func (e *attributeEncoder) AddUintptr(key string, value uintptr) {
+	e.add(key, uint64Value(uint64(value)))
+}
+
+// AddReflected flattens the JSON representation of value, the same one zap's JSON encoder prints
+func (e *attributeEncoder) AddReflected(key string, value interface{}) error {
+	decoded, err := decodeReflected(value)
+	if err != nil {
+		return err
+	}
+	return e.addDecoded(key, decoded)
+}
+
+// addDecoded flattens value decoded by decodeReflected, nested values are walked without encoding them again
+func (e *attributeEncoder) addDecoded(key string, decoded interface{}) error {
+	switch v := decoded.(type) {
+	case map[string]interface{}:
+		return e.AddObject(key, jsonObject(v))
+	case []interface{}:
+		return e.AddArray(key, jsonArray(v))
+	}
+	e.add(key, jsonValue(decoded))
+	return nil
+}
+
+func (e *attributeEncoder) OpenNamespace(key string) {
+	e.prefix += key + "."
+}
+
+// arrayEncoder is zapcore.ArrayEncoder collecting primitive elements into a typed slice attribute.
+// Objects and arrays nested in the array are flattened with the element index, e.g. "users.0.name".
+type arrayEncoder struct {
+	object *attributeEncoder
+	key    string
+	values []attribute.Value
+	index  int
+}
+
+var _ zapcore.ArrayEncoder = &arrayEncoder{}
+
+func (a *arrayEncoder) append(value attribute.Value) {
+	if len(a.values) < maxArrayLength {
+		a.values = append(a.values, value)
+	}
+	a.index++
+}
+
+// flush adds collected primitive elements as a single slice attribute
+func (a *arrayEncoder) flush() {
+	if len(a.values) == 0 && a.index > 0 {
+		return
+	}
+	a.object.add(a.key, sliceValue(a.values))
+}
+
+func (a *arrayEncoder) elementKey() string {
+	return a.key + "." + strconv.Itoa(a.index)
+}
+
+func (a *arrayEncoder) AppendArray(marshaler zapcore.ArrayMarshaler) error {
+	defer func() { a.index++ }()
+	if a.index >= maxArrayLength {
+		return nil
+	}
+	return a.object.AddArray(a.elementKey(), marshaler)
+}
+
+func (a *arrayEncoder) AppendObject(marshaler zapcore.ObjectMarshaler) error {
+	defer func() { a.index++ }()
+	if a.index >= maxArrayLength {
+		return nil
+	}
+	return a.object.AddObject(a.elementKey(), marshaler)
+}
+
+func (a *arrayEncoder) AppendReflected(value interface{}) error {
+	decoded, err := decodeReflected(value)
+	if err != nil {
+		return err
+	}
+	return a.appendDecoded(decoded)
+}
+
+// appendDecoded appends value decoded by decodeReflected, nested values are walked without encoding them again
+func (a *arrayEncoder) appendDecoded(decoded interface{}) error {
+	switch v := decoded.(type) {
+	case map[string]interface{}:
+		return a.AppendObject(jsonObject(v))
+	case []interface{}:
+		return a.AppendArray(jsonArray(v))
+	}
+	a.append(jsonValue(decoded))
+	return nil
+}
+
+func (a *arrayEncoder) AppendBool(value bool) {
+	a.append(attribute.BoolValue(value))
+}
+
+func (a *arrayEncoder) AppendByteString(value []byte) {
+	a.append(attribute.StringValue(string(value)))
+}
+
+func (a *arrayEncoder) AppendComplex128(value complex128) {
+	a.append(attribute.StringValue(strconv.FormatComplex(value, 'g', -1, 128)))
+}
+
+func (a *arrayEncoder) AppendComplex64(value complex64) {
+	a.append(attribute.StringValue(strconv.FormatComplex(complex128(value), 'g', -1, 64)))
+}
+
+func (a *arrayEncoder) AppendFloat64(value float64) {
+	a.append(attribute.Float64Value(value))
+}
+
+func (a *arrayEncoder) AppendFloat32(value float32) {
+	a.append(attribute.Float64Value(float64(value)))
+}
+
+func (a *arrayEncoder) AppendInt(value int) {
+	a.append(attribute.Int64Value(int64(value)))
+}
+
+func (a *arrayEncoder) AppendInt64(value int64) {
+	a.append(attribute.Int64Value(value))
+}
+
+func (a *arrayEncoder) AppendInt32(value int32) {
+	a.append(attribute.Int64Value(int64(value)))
+}
+
+func (a *arrayEncoder) AppendInt16(value int16) {
+	a.append(attribute.Int64Value(int64(value)))
+}
+
+func (a *arrayEncoder) AppendInt8(value int8) {
+	a.append(attribute.Int64Value(int64(value)))
+}
+
+func (a *arrayEncoder) AppendString(value string) {
+	a.append(attribute.StringValue(value))
+}
+
+func (a *arrayEncoder) AppendUint(value uint) {
+	a.append(uint64Value(uint64(value)))
+}
+
+func (a *arrayEncoder) AppendUint64(value uint64) {
+	a.append(uint64Value(uint64(value)))
+}
+
+func (a *arrayEncoder) AppendUint32(value uint32) {
+	a.append(attribute.Int64Value(int64(value)))
+}
+
+func (a *arrayEncoder) AppendUint16(value uint16) {
+	a.append(attribute.Int64Value(int64(value)))
+}
+
+func (a *arrayEncoder) AppendUint8(value uint8) {
+	a.append(attribute.Int64Value(int64(value)))
+}
+
+func (a *arrayEncoder) AppendUintptr(value uintptr) {
+	a.append(uint64Value(uint64(value)))
+}
+
+func (a *arrayEncoder) AppendDuration(value time.Duration) {
+	a.append(attribute.Float64Value(value.Seconds()))
+}
+
+func (a *arrayEncoder) AppendTime(value time.Time) {
+	a.append(attribute.Int64Value(value.Unix()))
+}
+
+// uint64Value converts value into int64 attribute value, values above math.MaxInt64 don't fit and are exported
+// as decimal string
+func uint64Value(value uint64) attribute.Value {
+	if value > math.MaxInt64 {
+		return attribute.StringValue(strconv.FormatUint(value, 10))
+	}
+	return attribute.Int64Value(int64(value))
+}
+
+// sliceValue converts elements into slice of their common type,
+// integers mixed with floats become floats and other mixes are printed as strings
+func sliceValue(values []attribute.Value) attribute.Value {
+	bools, ints, floats := 0, 0, 0
+	for _, value := range values {
+		switch value.Type() {
+		case attribute.BOOL:
+			bools++
+		case attribute.INT64:
+			ints++
+		case attribute.FLOAT64:
+			floats++
+		}
+	}
+
+	switch {
+	case len(values) == 0:
+		return attribute.StringSliceValue([]string{})
+	case bools == len(values):
+		result := make([]bool, len(values))
+		for i, value := range values {
+			result[i] = value.AsBool()
+		}
+		return attribute.BoolSliceValue(result)
+	case ints == len(values):
+		result := make([]int64, len(values))
+		for i, value := range values {
+			result[i] = value.AsInt64()
+		}
+		return attribute.Int64SliceValue(result)
+	case ints+floats == len(values):
+		result := make([]float64, len(values))
+		for i, value := range values {
+			if value.Type() == attribute.INT64 {
+				result[i] = float64(value.AsInt64())
+			} else {
+				result[i] = value.AsFloat64()
+			}
+		}
+		return attribute.Float64SliceValue(result)
+	}
+	result := make([]string, len(values))
+	for i, value := range values {
+		result[i] = value.Emit()
+	}
+	return attribute.StringSliceValue(result)
+}
+
+// decodeReflected converts value into its generic JSON form, keeping integers exact
+func decodeReflected(value interface{}) (interface{}, error) {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.UseNumber()
+	var decoded interface{}
+	if err := decoder.Decode(&decoded); err != nil {
+		return nil, err
+	}
+	return decoded, nil
+}
+
+// jsonValue converts decoded JSON primitive into attribute value
+func jsonValue(value interface{}) attribute.Value {
+	switch v := value.(type) {
+	case nil:
+		return attribute.StringValue("<nil>")
+	case bool:
+		return attribute.BoolValue(v)
+	case string:
+		return attribute.StringValue(v)
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return attribute.Int64Value(i)
+		}
+		if u, err := strconv.ParseUint(v.String(), 10, 64); err == nil {
+			return uint64Value(u)
+		}
+		f, _ := v.Float64()
+		return attribute.Float64Value(f)
+	}
+	return attribute.StringValue(fmt.Sprint(value))
+}
+
+// jsonObject marshals decoded JSON object with sorted keys, otelzap encoders walk its values directly
+type jsonObject map[string]interface{}
+
+func (o jsonObject) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	keys := make([]string, 0, len(o))
+	for key := range o {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	e, decoded := enc.(*attributeEncoder)
+	for _, key := range keys {
+		var err error
+		if decoded {
+			err = e.addDecoded(key, o[key])
+		} else {
+			err = enc.AddReflected(key, o[key])
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// jsonArray marshals decoded JSON array, otelzap encoders walk its values directly
+type jsonArray []interface{}
+
+func (a jsonArray) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	arr, decoded := enc.(*arrayEncoder)
+	for _, value := range a {
+		var err error
+		if decoded {
+			err = arr.appendDecoded(value)
+		} else {
+			err = enc.AppendReflected(value)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
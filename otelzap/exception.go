@@ -0,0 +1,124 @@
+/*
+Copyright Agoda Services Co.,Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package otelzap
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.20.0"
+	"go.uber.org/zap/zapcore"
+)
+
+// errorKey is the key of zap.Error fields, exported with exception semantic conventions
+const errorKey = "error"
+
+// codeStacktraceKey is the key of the stack trace captured by zap.AddStacktrace, newer semantic conventions
+// define it for the stack of the log statement
+const codeStacktraceKey = attribute.Key("code.stacktrace")
+
+// otelError converts error into exception.message, exception.type and exception.stacktrace attributes.
+// Errors with other keys than "error", e.g. zap.NamedError("db", err), keep their key in place of "exception",
+// so several errors of one entry don't overwrite each other.
+func otelError(key string, err error) []attribute.KeyValue {
+	messageKey, typeKey, stacktraceKey := semconv.ExceptionMessageKey, semconv.ExceptionTypeKey, semconv.ExceptionStacktraceKey
+	if key != errorKey {
+		messageKey, typeKey, stacktraceKey = attribute.Key(key+".message"), attribute.Key(key+".type"), attribute.Key(key+".stacktrace")
+	}
+
+	if v := reflect.ValueOf(err); v.Kind() == reflect.Ptr && v.IsNil() {
+		return []attribute.KeyValue{messageKey.String("<nil>"), typeKey.String(v.Type().String())}
+	}
+
+	attributes := []attribute.KeyValue{
+		messageKey.String(err.Error()),
+		typeKey.String(reflect.TypeOf(err).String()),
+	}
+	if stacktrace := errorStacktrace(err); stacktrace != "" {
+		attributes = append(attributes, stacktraceKey.String(stacktrace))
+	}
+	return attributes
+}
+
+// errorStacktrace returns the verbose form of errors implementing fmt.Formatter, the same zap prints as "errorVerbose",
+// e.g. message followed by the stack trace of github.com/pkg/errors
+func errorStacktrace(err error) string {
+	if _, ok := err.(fmt.Formatter); !ok {
+		return ""
+	}
+	verbose := fmt.Sprintf("%+v", err)
+	if verbose == err.Error() {
+		return ""
+	}
+	return verbose
+}
+
+// callerAttributes returns code.* attributes of the log statement, empty when zap.AddCaller isn't used
+func callerAttributes(caller zapcore.EntryCaller) []attribute.KeyValue {
+	if !caller.Defined {
+		return nil
+	}
+	attributes := []attribute.KeyValue{
+		semconv.CodeFilepath(caller.File),
+		semconv.CodeLineNumber(caller.Line),
+	}
+	if caller.Function != "" {
+		namespace, function := splitFunctionName(caller.Function)
+		attributes = append(attributes, semconv.CodeFunction(function))
+		if namespace != "" {
+			attributes = append(attributes, semconv.CodeNamespace(namespace))
+		}
+	}
+	return attributes
+}
+
+// splitFunctionName splits fully qualified function name like "github.com/org/repo/pkg.(*Type).Method"
+// into namespace "github.com/org/repo/pkg.(*Type)" and function "Method".
+// Type arguments of generic functions and types like "pkg.Map[...]" are dropped, they may contain dots.
+// Same as in otelslog, the modules don't depend on each other.
+func splitFunctionName(name string) (string, string) {
+	name = stripTypeArguments(name)
+	lastSlash := strings.LastIndex(name, "/")
+	lastDot := strings.LastIndex(name[lastSlash+1:], ".")
+	if lastDot < 0 {
+		return "", name
+	}
+	lastDot += lastSlash + 1
+	return name[:lastDot], name[lastDot+1:]
+}
+
+// stripTypeArguments removes bracketed type arguments, e.g. "pkg.(*Type[...]).Method" becomes "pkg.(*Type).Method"
+func stripTypeArguments(name string) string {
+	if !strings.Contains(name, "[") {
+		return name
+	}
+	var b strings.Builder
+	depth := 0
+	for _, r := range name {
+		switch {
+		case r == '[':
+			depth++
+		case r == ']' && depth > 0:
+			depth--
+		case depth == 0:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
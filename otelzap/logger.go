@@ -18,13 +18,13 @@ package otelzap
 
 import (
 	"context"
+	"fmt"
 
-	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
 
-// Logger is a thin wrapper for zap.Logger that adds Ctx method.
+// Logger is a thin wrapper for zap.Logger that adds Ctx and context-first logging methods.
 type Logger struct {
 	*zap.Logger
 }
@@ -37,12 +37,9 @@ func (l *Logger) Sugar() *SugaredLogger {
 	}
 }
 
+// Ctx returns logger exporting records with the trace context of ctx, see Context
 func (l *Logger) Ctx(ctx context.Context) *Logger {
-	span := trace.SpanFromContext(ctx)
-	if span.SpanContext().IsValid() {
-		return l.With(zap.Reflect(contextKey, span.SpanContext()))
-	}
-	return l
+	return l.With(Context(ctx))
 }
 
 func (l *Logger) With(fields ...zapcore.Field) *Logger {
@@ -51,18 +48,256 @@ func (l *Logger) With(fields ...zapcore.Field) *Logger {
 	}
 }
 
+// Named adds a new path segment to the logger's name, see zap.Logger.Named
+func (l *Logger) Named(name string) *Logger {
+	return &Logger{
+		Logger: l.Logger.Named(name),
+	}
+}
+
+// WithOptions clones the logger and applies the options, see zap.Logger.WithOptions
+func (l *Logger) WithOptions(opts ...zap.Option) *Logger {
+	return &Logger{
+		Logger: l.Logger.WithOptions(opts...),
+	}
+}
+
+// LogContext logs a message at the level with the trace context of ctx
+func (l *Logger) LogContext(ctx context.Context, lvl zapcore.Level, msg string, fields ...zapcore.Field) {
+	l.logContext(ctx, lvl, msg, fields)
+}
+
+// DebugContext logs a message at DebugLevel with the trace context of ctx
+func (l *Logger) DebugContext(ctx context.Context, msg string, fields ...zapcore.Field) {
+	l.logContext(ctx, zapcore.DebugLevel, msg, fields)
+}
+
+// InfoContext logs a message at InfoLevel with the trace context of ctx
+func (l *Logger) InfoContext(ctx context.Context, msg string, fields ...zapcore.Field) {
+	l.logContext(ctx, zapcore.InfoLevel, msg, fields)
+}
+
+// WarnContext logs a message at WarnLevel with the trace context of ctx
+func (l *Logger) WarnContext(ctx context.Context, msg string, fields ...zapcore.Field) {
+	l.logContext(ctx, zapcore.WarnLevel, msg, fields)
+}
+
+// ErrorContext logs a message at ErrorLevel with the trace context of ctx
+func (l *Logger) ErrorContext(ctx context.Context, msg string, fields ...zapcore.Field) {
+	l.logContext(ctx, zapcore.ErrorLevel, msg, fields)
+}
+
+// DPanicContext logs a message at DPanicLevel with the trace context of ctx, the logger then panics in development
+func (l *Logger) DPanicContext(ctx context.Context, msg string, fields ...zapcore.Field) {
+	l.logContext(ctx, zapcore.DPanicLevel, msg, fields)
+}
+
+// PanicContext logs a message at PanicLevel with the trace context of ctx, the logger then panics
+func (l *Logger) PanicContext(ctx context.Context, msg string, fields ...zapcore.Field) {
+	l.logContext(ctx, zapcore.PanicLevel, msg, fields)
+}
+
+// FatalContext logs a message at FatalLevel with the trace context of ctx, the logger then calls os.Exit(1)
+func (l *Logger) FatalContext(ctx context.Context, msg string, fields ...zapcore.Field) {
+	l.logContext(ctx, zapcore.FatalLevel, msg, fields)
+}
+
+// logContextCallerSkip is the number of frames between the caller of ...Context methods and zap,
+// compared to calling zap directly
+const logContextCallerSkip = 2
+
+func (l *Logger) logContext(ctx context.Context, lvl zapcore.Level, msg string, fields []zapcore.Field) {
+	// the caller-skipped logger is built only for enabled entries, so Logger stays a plain wrapper and With or Ctx
+	// don't clone twice; DPanic and above run their hooks even when disabled
+	if lvl < zapcore.DPanicLevel && !l.Core().Enabled(lvl) {
+		return
+	}
+	logger := l.Logger.WithOptions(zap.AddCallerSkip(logContextCallerSkip))
+	if ce := logger.Check(lvl, msg); ce != nil {
+		ce.Write(append([]zapcore.Field{Context(ctx)}, fields...)...)
+	}
+}
+
 type SugaredLogger struct {
 	*zap.SugaredLogger
 }
 
+// Ctx returns logger exporting records with the trace context of ctx, see Context
 func (l *SugaredLogger) Ctx(ctx context.Context) *SugaredLogger {
-	span := trace.SpanFromContext(ctx)
-	if span.SpanContext().IsValid() {
-		return &SugaredLogger{
-			SugaredLogger: l.With(zap.Reflect(contextKey, span.SpanContext())),
-		}
+	return l.With(Context(ctx))
+}
+
+// Desugar unwraps the SugaredLogger, see zap.SugaredLogger.Desugar
+func (l *SugaredLogger) Desugar() *Logger {
+	return &Logger{
+		Logger: l.SugaredLogger.Desugar(),
+	}
+}
+
+// With adds a variadic number of fields to the logging context, see zap.SugaredLogger.With
+func (l *SugaredLogger) With(args ...interface{}) *SugaredLogger {
+	return &SugaredLogger{
+		SugaredLogger: l.SugaredLogger.With(args...),
+	}
+}
+
+// Named adds a new path segment to the logger's name, see zap.SugaredLogger.Named
+func (l *SugaredLogger) Named(name string) *SugaredLogger {
+	return &SugaredLogger{
+		SugaredLogger: l.SugaredLogger.Named(name),
 	}
+}
+
+// WithOptions clones the logger and applies the options, see zap.SugaredLogger.WithOptions
+func (l *SugaredLogger) WithOptions(opts ...zap.Option) *SugaredLogger {
 	return &SugaredLogger{
-		SugaredLogger: l.SugaredLogger,
+		SugaredLogger: l.SugaredLogger.WithOptions(opts...),
+	}
+}
+
+// DebugContext logs the arguments at DebugLevel with the trace context of ctx, like Debug
+func (l *SugaredLogger) DebugContext(ctx context.Context, args ...interface{}) {
+	l.logContext(ctx, zapcore.DebugLevel, "", args, nil)
+}
+
+// DebugfContext logs a templated message at DebugLevel with the trace context of ctx, like Debugf
+func (l *SugaredLogger) DebugfContext(ctx context.Context, template string, args ...interface{}) {
+	l.logContext(ctx, zapcore.DebugLevel, template, args, nil)
+}
+
+// DebugwContext logs a message with key-value pairs at DebugLevel with the trace context of ctx, like Debugw
+func (l *SugaredLogger) DebugwContext(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	l.logContext(ctx, zapcore.DebugLevel, msg, nil, keysAndValues)
+}
+
+// InfoContext logs the arguments at InfoLevel with the trace context of ctx, like Info
+func (l *SugaredLogger) InfoContext(ctx context.Context, args ...interface{}) {
+	l.logContext(ctx, zapcore.InfoLevel, "", args, nil)
+}
+
+// InfofContext logs a templated message at InfoLevel with the trace context of ctx, like Infof
+func (l *SugaredLogger) InfofContext(ctx context.Context, template string, args ...interface{}) {
+	l.logContext(ctx, zapcore.InfoLevel, template, args, nil)
+}
+
+// InfowContext logs a message with key-value pairs at InfoLevel with the trace context of ctx, like Infow
+func (l *SugaredLogger) InfowContext(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	l.logContext(ctx, zapcore.InfoLevel, msg, nil, keysAndValues)
+}
+
+// WarnContext logs the arguments at WarnLevel with the trace context of ctx, like Warn
+func (l *SugaredLogger) WarnContext(ctx context.Context, args ...interface{}) {
+	l.logContext(ctx, zapcore.WarnLevel, "", args, nil)
+}
+
+// WarnfContext logs a templated message at WarnLevel with the trace context of ctx, like Warnf
+func (l *SugaredLogger) WarnfContext(ctx context.Context, template string, args ...interface{}) {
+	l.logContext(ctx, zapcore.WarnLevel, template, args, nil)
+}
+
+// WarnwContext logs a message with key-value pairs at WarnLevel with the trace context of ctx, like Warnw
+func (l *SugaredLogger) WarnwContext(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	l.logContext(ctx, zapcore.WarnLevel, msg, nil, keysAndValues)
+}
+
+// ErrorContext logs the arguments at ErrorLevel with the trace context of ctx, like Error
+func (l *SugaredLogger) ErrorContext(ctx context.Context, args ...interface{}) {
+	l.logContext(ctx, zapcore.ErrorLevel, "", args, nil)
+}
+
+// ErrorfContext logs a templated message at ErrorLevel with the trace context of ctx, like Errorf
+func (l *SugaredLogger) ErrorfContext(ctx context.Context, template string, args ...interface{}) {
+	l.logContext(ctx, zapcore.ErrorLevel, template, args, nil)
+}
+
+// ErrorwContext logs a message with key-value pairs at ErrorLevel with the trace context of ctx, like Errorw
+func (l *SugaredLogger) ErrorwContext(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	l.logContext(ctx, zapcore.ErrorLevel, msg, nil, keysAndValues)
+}
+
+// DPanicContext logs the arguments at DPanicLevel with the trace context of ctx, like DPanic, the logger then panics in development
+func (l *SugaredLogger) DPanicContext(ctx context.Context, args ...interface{}) {
+	l.logContext(ctx, zapcore.DPanicLevel, "", args, nil)
+}
+
+// DPanicfContext logs a templated message at DPanicLevel with the trace context of ctx, like DPanicf, the logger then panics in development
+func (l *SugaredLogger) DPanicfContext(ctx context.Context, template string, args ...interface{}) {
+	l.logContext(ctx, zapcore.DPanicLevel, template, args, nil)
+}
+
+// DPanicwContext logs a message with key-value pairs at DPanicLevel with the trace context of ctx, like DPanicw, the logger then panics in development
+func (l *SugaredLogger) DPanicwContext(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	l.logContext(ctx, zapcore.DPanicLevel, msg, nil, keysAndValues)
+}
+
+// PanicContext logs the arguments at PanicLevel with the trace context of ctx, like Panic, the logger then panics
+func (l *SugaredLogger) PanicContext(ctx context.Context, args ...interface{}) {
+	l.logContext(ctx, zapcore.PanicLevel, "", args, nil)
+}
+
+// PanicfContext logs a templated message at PanicLevel with the trace context of ctx, like Panicf, the logger then panics
+func (l *SugaredLogger) PanicfContext(ctx context.Context, template string, args ...interface{}) {
+	l.logContext(ctx, zapcore.PanicLevel, template, args, nil)
+}
+
+// PanicwContext logs a message with key-value pairs at PanicLevel with the trace context of ctx, like Panicw, the logger then panics
+func (l *SugaredLogger) PanicwContext(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	l.logContext(ctx, zapcore.PanicLevel, msg, nil, keysAndValues)
+}
+
+// FatalContext logs the arguments at FatalLevel with the trace context of ctx, like Fatal, the logger then calls os.Exit(1)
+func (l *SugaredLogger) FatalContext(ctx context.Context, args ...interface{}) {
+	l.logContext(ctx, zapcore.FatalLevel, "", args, nil)
+}
+
+// FatalfContext logs a templated message at FatalLevel with the trace context of ctx, like Fatalf, the logger then calls os.Exit(1)
+func (l *SugaredLogger) FatalfContext(ctx context.Context, template string, args ...interface{}) {
+	l.logContext(ctx, zapcore.FatalLevel, template, args, nil)
+}
+
+// FatalwContext logs a message with key-value pairs at FatalLevel with the trace context of ctx, like Fatalw, the logger then calls os.Exit(1)
+func (l *SugaredLogger) FatalwContext(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	l.logContext(ctx, zapcore.FatalLevel, msg, nil, keysAndValues)
+}
+
+func (l *SugaredLogger) logContext(ctx context.Context, lvl zapcore.Level, template string, fmtArgs []interface{}, keysAndValues []interface{}) {
+	// skipped before formatting the message, Level is the lowest enabled level of the core
+	if lvl < zapcore.DPanicLevel && lvl < l.Level() {
+		return
+	}
+	sugar := l.SugaredLogger.WithOptions(zap.AddCallerSkip(logContextCallerSkip))
+	msg := message(template, fmtArgs)
+	keysAndValues = append([]interface{}{Context(ctx)}, keysAndValues...)
+	switch lvl {
+	case zapcore.DebugLevel:
+		sugar.Debugw(msg, keysAndValues...)
+	case zapcore.InfoLevel:
+		sugar.Infow(msg, keysAndValues...)
+	case zapcore.WarnLevel:
+		sugar.Warnw(msg, keysAndValues...)
+	case zapcore.ErrorLevel:
+		sugar.Errorw(msg, keysAndValues...)
+	case zapcore.DPanicLevel:
+		sugar.DPanicw(msg, keysAndValues...)
+	case zapcore.PanicLevel:
+		sugar.Panicw(msg, keysAndValues...)
+	case zapcore.FatalLevel:
+		sugar.Fatalw(msg, keysAndValues...)
+	}
+}
+
+// message formats the message the same way zap.SugaredLogger does
+func message(template string, fmtArgs []interface{}) string {
+	if len(fmtArgs) == 0 {
+		return template
+	}
+	if template != "" {
+		return fmt.Sprintf(template, fmtArgs...)
+	}
+	if len(fmtArgs) == 1 {
+		if str, ok := fmtArgs[0].(string); ok {
+			return str
+		}
 	}
+	return fmt.Sprint(fmtArgs...)
 }
@@ -0,0 +1,68 @@
+package otelzap
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestLogger_ContextMethods(t *testing.T) {
+	loggerProvider, exporter := newMemoryProvider()
+	var buf bytes.Buffer
+	jsonCore := zapcore.NewCore(zapcore.NewJSONEncoder(zapcore.EncoderConfig{
+		MessageKey:   "msg",
+		CallerKey:    "caller",
+		EncodeCaller: zapcore.ShortCallerEncoder,
+	}), zapcore.AddSync(&buf), zap.DebugLevel)
+	logger := &Logger{zap.New(zapcore.NewTee(jsonCore, NewOtelCore(loggerProvider, WithLevel(zap.DebugLevel), WithCaller(false))), zap.AddCaller())}
+	sugar := logger.Sugar()
+	ctx := testSpanContext()
+
+	_, file, line, _ := runtime.Caller(0)
+	logger.InfoContext(ctx, "info", zap.String("key", "value"))
+	logger.LogContext(ctx, zap.WarnLevel, "log")
+	sugar.DebugContext(ctx, "debug ", 1)
+	sugar.ErrorfContext(ctx, "error %d", 2)
+	sugar.WarnwContext(ctx, "warn", "key", "value")
+	logger.Named("chain").WithOptions(zap.Fields(zap.Int("bound", 1))).Sugar().Named("sugar").With("with", true).InfoContext(ctx, "chained")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	expected := []string{"info", "log", "debug 1", "error 2", "warn", "chained"}
+	assert.Len(t, lines, len(expected))
+	for i, msg := range expected {
+		var entry map[string]interface{}
+		assert.NoError(t, json.Unmarshal([]byte(lines[i]), &entry))
+		assert.Equal(t, msg, entry["msg"])
+		assert.Equal(t, fmt.Sprintf("otelzap/%s:%d", filepath.Base(file), line+1+i), entry["caller"], msg)
+		assert.Equal(t, "0102030405060708090a0b0c0d0e0f10", entry["trace_id"], msg)
+	}
+
+	assert.Len(t, exporter.records, len(expected))
+	for _, record := range exporter.records {
+		assert.True(t, record.TraceId().IsValid())
+	}
+	assert.Equal(t, map[string]interface{}{"key": "value"}, attributeMap(exporter.records[0]))
+	assert.Equal(t, map[string]interface{}{"bound": int64(1), "with": true}, attributeMap(exporter.records[5]))
+}
+
+func TestLogger_ContextMethodsDisabled(t *testing.T) {
+	loggerProvider, exporter := newMemoryProvider()
+	logger := &Logger{zap.New(NewOtelCore(loggerProvider, WithLevel(zap.WarnLevel)))}
+
+	logger.InfoContext(context.Background(), "info")
+	logger.Sugar().InfofContext(context.Background(), "info %s", "formatted")
+	assert.Empty(t, exporter.records)
+
+	assert.Panics(t, func() { logger.PanicContext(context.Background(), "panic") })
+	assert.Panics(t, func() { logger.Sugar().PanicwContext(context.Background(), "panic") })
+	assert.Len(t, exporter.records, 2)
+}
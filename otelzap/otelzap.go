@@ -17,41 +17,178 @@ limitations under the License.
 package otelzap
 
 import (
+	"context"
+	"os"
+	"sync"
+	"time"
+
 	otel "github.com/agoda-com/opentelemetry-logs-go/logs"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
 
-// NewOtelCore creates new OpenTelemetry Core to export logs in OTLP format
+// defaultFlushTimeout bounds the time Sync waits for the logger provider to export buffered records
+const defaultFlushTimeout = 5 * time.Second
+
+// Flusher exports buffered records, e.g. sdk.LoggerProvider
+type Flusher interface {
+	ForceFlush(ctx context.Context) error
+}
+
+// NewOtelCore creates new OpenTelemetry Core to export logs in OTLP format.
+// When loggerProvider implements Flusher, e.g. sdk.LoggerProvider, Sync of the core flushes it.
+// Options of NewProduction and NewDevelopment loggers are ignored.
 func NewOtelCore(loggerProvider otel.LoggerProvider, opts ...Option) zapcore.Core {
+	return newConfig(loggerProvider, opts).otlpCore
+}
+
+// Option is a function that applies an option to an OpenTelemetry Core or a logger built with NewProduction
+// and NewDevelopment
+type Option func(c *config)
+
+// config is the OpenTelemetry Core with options of the loggers built with NewProduction and NewDevelopment
+type config struct {
+	*otlpCore
+
+	consoleWriter  zapcore.WriteSyncer
+	consoleEnabler zapcore.LevelEnabler
+	sampling       *zap.SamplingConfig
+	samplingTarget SamplingTarget
+}
+
+// newConfig creates the core and applies opts in order, so later options override earlier ones
+func newConfig(loggerProvider otel.LoggerProvider, opts []Option) *config {
 	logger := loggerProvider.Logger(
 		instrumentationScope.Name,
 		otel.WithInstrumentationVersion(instrumentationScope.Version),
 	)
 
-	c := &otlpCore{
-		logger:       logger,
-		levelEnabler: zapcore.InfoLevel,
+	c := &config{
+		otlpCore: &otlpCore{
+			logger:         logger,
+			loggerProvider: loggerProvider,
+			levelEnabler:   zapcore.InfoLevel,
+			addCaller:      true,
+			severityMapper: DefaultSeverityMapper,
+			flushTimeout:   defaultFlushTimeout,
+		},
+		consoleWriter:  zapcore.Lock(os.Stderr),
+		consoleEnabler: zapcore.InfoLevel,
+	}
+	if flusher, ok := loggerProvider.(Flusher); ok {
+		c.flusher = flusher
 	}
 	for _, apply := range opts {
 		apply(c)
 	}
-
 	return c
 }
 
-// Option is a function that applies an option to an OpenTelemetry Core
-type Option func(c *otlpCore)
-
 // WithLevel sets the minimum level for the OpenTelemetry Core log to be exported
 func WithLevel(level zapcore.Level) Option {
-	return Option(func(c *otlpCore) {
+	return Option(func(c *config) {
 		c.levelEnabler = level
 	})
 }
 
 // WithLevelEnabler sets the zapcore.LevelEnabler for determining which log levels to export
 func WithLevelEnabler(levelEnabler zapcore.LevelEnabler) Option {
-	return Option(func(c *otlpCore) {
+	return Option(func(c *config) {
 		c.levelEnabler = levelEnabler
 	})
 }
+
+// SeverityMapper converts zap level into OpenTelemetry severity number and severity text.
+// The core clamps returned severity number into the valid range of TRACE (1) to FATAL4 (24).
+type SeverityMapper func(level zapcore.Level) (otel.SeverityNumber, string)
+
+// DefaultSeverityMapper maps zap levels to the severity of the same name, DPanic to ERROR2, Panic to ERROR3,
+// custom levels below Debug to TRACE severities and above Fatal to FATAL severities.
+// The severity text is level.String(), e.g. "dpanic".
+func DefaultSeverityMapper(level zapcore.Level) (otel.SeverityNumber, string) {
+	return otelLevel(level), level.String()
+}
+
+// WithSeverityMapper sets the conversion of zap levels into severity number and text, e.g. for custom levels,
+// nil restores DefaultSeverityMapper
+func WithSeverityMapper(mapper SeverityMapper) Option {
+	return Option(func(c *config) {
+		if mapper == nil {
+			mapper = DefaultSeverityMapper
+		}
+		c.severityMapper = mapper
+	})
+}
+
+// WithCaller sets whether the caller of entries logged with zap.AddCaller is exported
+// as code.filepath, code.lineno, code.function and code.namespace attributes, enabled by default
+func WithCaller(enabled bool) Option {
+	return Option(func(c *config) {
+		c.addCaller = enabled
+	})
+}
+
+// WithFlusher sets the Flusher called by Sync and after Panic and Fatal entries, nil disables flushing
+func WithFlusher(flusher Flusher) Option {
+	return Option(func(c *config) {
+		c.flusher = flusher
+	})
+}
+
+// WithFlushTimeout sets the maximum time Sync waits for the Flusher, 5 seconds by default.
+// Zero or negative timeout means no deadline, Sync waits until the Flusher returns.
+func WithFlushTimeout(timeout time.Duration) Option {
+	return Option(func(c *config) {
+		c.flushTimeout = timeout
+	})
+}
+
+// WithLoggerNameKey exports the name of loggers created with zap.Logger.Named as attribute with the given key,
+// e.g. "logger.name"
+func WithLoggerNameKey(key string) Option {
+	return Option(func(c *config) {
+		c.loggerNameKey = key
+	})
+}
+
+// WithLoggerNameScope exports records of loggers created with zap.Logger.Named with the logger name
+// as instrumentation scope name, so every subsystem appears as its own scope
+func WithLoggerNameScope() Option {
+	return Option(func(c *config) {
+		c.scopedLoggers = &sync.Map{}
+	})
+}
+
+// WithBaggage adds members of the baggage of the Context field as attributes
+func WithBaggage() Option {
+	return Option(func(c *config) {
+		c.addBaggage = true
+	})
+}
+
+// WithBaggageAllow adds only baggage members with keys matching one of the patterns (path.Match syntax),
+// it implies WithBaggage
+func WithBaggageAllow(patterns ...string) Option {
+	return Option(func(c *config) {
+		c.addBaggage = true
+		c.baggageAllow = append(c.baggageAllow, patterns...)
+	})
+}
+
+// WithBaggageDeny never adds baggage members with keys matching one of the patterns (path.Match syntax),
+// it takes precedence over WithBaggageAllow and implies WithBaggage
+func WithBaggageDeny(patterns ...string) Option {
+	return Option(func(c *config) {
+		c.addBaggage = true
+		c.baggageDeny = append(c.baggageDeny, patterns...)
+	})
+}
+
+// WithContextExtractors adds attributes returned by the extractors for the context of the Context field,
+// e.g. request-scoped values
+func WithContextExtractors(extractors ...func(context.Context) []attribute.KeyValue) Option {
+	return Option(func(c *config) {
+		c.contextExtractors = append(c.contextExtractors, extractors...)
+	})
+}